@@ -0,0 +1,106 @@
+package stats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ArchCtl represents /dev/archctl.
+type ArchCtl struct {
+	CPU       CPUType
+	HavePGE   bool   // whether the processor supports page global extension
+	PGE       bool   // whether page global extension is enabled
+	Coherence string // method of memory coherence, such as mfence
+	CmpSwap   string // implementation of compare-and-swap, such as cmpswap486
+	I8253Set  bool   // whether i8253 timer is set
+
+	DefaultCache string         // default memory cache type, such as wb
+	Caches       []*CacheRegion // memory cache ranges
+
+	Others []string // unrecognized lines
+}
+
+// CacheRegion represents a memory range with the cache type such as uc, wb or wc.
+type CacheRegion struct {
+	Base uint64
+	Size uint64
+	Type string
+}
+
+// ReadArchCtl reads architecture specific settings from /dev/archctl.
+func ReadArchCtl(ctx context.Context, opts ...Option) (*ArchCtl, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "/dev/archctl")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var c ArchCtl
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" {
+			continue
+		}
+		if err := parseArchCtl(s, &c); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func parseArchCtl(s string, c *ArchCtl) error {
+	fields := strings.Fields(s)
+	switch {
+	case fields[0] == "cpu" && len(fields) >= 3:
+		v := strings.TrimSpace(s[len("cpu"):])
+		if strings.HasSuffix(v, " pge") {
+			c.HavePGE = true
+			v = strings.TrimSuffix(v, " pge")
+		}
+		i := strings.LastIndexByte(v, ' ')
+		if i < 0 {
+			return fmt.Errorf("invalid cpu: %s", s)
+		}
+		clock, err := strconv.Atoi(v[i+1:])
+		if err != nil {
+			return err
+		}
+		c.CPU.Name = v[:i]
+		c.CPU.Clock = clock
+	case fields[0] == "pge" && len(fields) == 2:
+		c.PGE = fields[1] == "on"
+	case fields[0] == "coherence" && len(fields) == 2:
+		c.Coherence = fields[1]
+	case fields[0] == "cmpswap" && len(fields) == 2:
+		c.CmpSwap = fields[1]
+	case fields[0] == "i8253set" && len(fields) == 2:
+		c.I8253Set = fields[1] == "on"
+	case fields[0] == "cache" && len(fields) == 3 && fields[1] == "default":
+		c.DefaultCache = fields[2]
+	case fields[0] == "cache" && len(fields) == 4:
+		var p intParser
+		r := CacheRegion{
+			Base: p.ParseUint64(fields[1], 0),
+			Size: p.ParseUint64(fields[2], 0),
+			Type: fields[3],
+		}
+		if err := p.Err(); err != nil {
+			return err
+		}
+		c.Caches = append(c.Caches, &r)
+	default:
+		c.Others = append(c.Others, s)
+	}
+	return nil
+}
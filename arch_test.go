@@ -0,0 +1,34 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadArchCtl(t *testing.T) {
+	ctx := context.Background()
+	c, err := ReadArchCtl(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ArchCtl{
+		CPU: CPUType{
+			Name:  "Core i7/Xeon",
+			Clock: 2403,
+		},
+		HavePGE:      true,
+		PGE:          true,
+		Coherence:    "mfence",
+		CmpSwap:      "cmpswap486",
+		I8253Set:     true,
+		DefaultCache: "wb",
+		Caches: []*CacheRegion{
+			{Base: 0xe0000000, Size: 536870912, Type: "uc"},
+		},
+	}
+	if !cmp.Equal(want, c) {
+		t.Errorf("ReadArchCtl: %v", cmp.Diff(want, c))
+	}
+}
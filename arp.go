@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ARPEntry represents an entry of /net/arp.
+type ARPEntry struct {
+	Type         string // medium type such as ether
+	State        string // OK or WAIT
+	IP           net.IP
+	HardwareAddr net.HardwareAddr
+	IfcAddr      net.IP // local address of the interface; older kernels don't provide it
+}
+
+// ReadARP reads the ARP cache and IPv6 neighbor cache from arp.
+func ReadARP(ctx context.Context, opts ...Option) ([]*ARPEntry, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "arp")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var a []*ARPEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e, err := parseARPEntry(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		a = append(a, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseARPEntry parses a line formed as "type state ip hwaddr [ifcaddr]".
+func parseARPEntry(s string) (*ARPEntry, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 && len(fields) != 5 {
+		return nil, fmt.Errorf("invalid format: %s", s)
+	}
+	ip, err := parseIP(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	hw, err := hex.DecodeString(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hardware address: %s", fields[3])
+	}
+	e := ARPEntry{
+		Type:         fields[0],
+		State:        fields[1],
+		IP:           ip,
+		HardwareAddr: net.HardwareAddr(hw),
+	}
+	if len(fields) == 5 {
+		e.IfcAddr, err = parseIP(fields[4])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &e, nil
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadARP(t *testing.T) {
+	ctx := context.Background()
+	a, err := ReadARP(ctx, WithRootDir("testdata/net"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ifc := net.IPv4(192, 168, 1, 23).To4()
+	want := []*ARPEntry{
+		{
+			Type:         "ether",
+			State:        "OK",
+			IP:           net.IPv4(192, 168, 1, 1).To4(),
+			HardwareAddr: net.HardwareAddr{0x00, 0x0d, 0xb9, 0x12, 0x34, 0x56},
+			IfcAddr:      ifc,
+		},
+		{
+			Type:         "ether",
+			State:        "OK",
+			IP:           net.IPv4(192, 168, 1, 5).To4(),
+			HardwareAddr: net.HardwareAddr{0x52, 0x54, 0x09, 0x00, 0x83, 0x80},
+			IfcAddr:      ifc,
+		},
+		{
+			Type:         "ether",
+			State:        "WAIT",
+			IP:           net.IPv4(192, 168, 1, 7).To4(),
+			HardwareAddr: net.HardwareAddr{0, 0, 0, 0, 0, 0},
+			IfcAddr:      ifc,
+		},
+	}
+	if !cmp.Equal(want, a) {
+		t.Errorf("ReadARP: %v", cmp.Diff(want, a))
+	}
+}
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readDirIDs returns sorted numbers of entries named by decimal numbers in dir.
+// It ignores other entries, such as clone in a protocol directory or trace in /proc.
+func readDirIDs(dir string) ([]int, error) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	names, err := d.Readdirnames(0)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, s := range names {
+		n, err := strconv.ParseUint(s, 10, 31)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, int(n))
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// conv represents addresses of a conversation, such as /net/tcp/N.
+type conv struct {
+	LocalAddr  net.IP
+	LocalPort  int
+	RemoteAddr net.IP
+	RemotePort int
+}
+
+// readConv reads local and remote files of the conversation id in protodir,
+// and then calls parseStatus with the content of its status file.
+func readConv(protodir string, id int, parseStatus func(s string) error) (*conv, error) {
+	dir := filepath.Join(protodir, strconv.Itoa(id))
+	var (
+		c   conv
+		err error
+	)
+	c.LocalAddr, c.LocalPort, err = readConvAddr(filepath.Join(dir, "local"))
+	if err != nil {
+		return nil, err
+	}
+	c.RemoteAddr, c.RemotePort, err = readConvAddr(filepath.Join(dir, "remote"))
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "status")
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseStatus(string(b)); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return &c, nil
+}
+
+// readConvAddr reads file, that is local or remote of a conversation,
+// and returns its IP address and port.
+func readConvAddr(file string) (net.IP, int, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	ip, port, err := parseConvAddr(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file, err)
+	}
+	return ip, port, nil
+}
+
+// parseConvAddr parses s formed as "addr!port".
+func parseConvAddr(s string) (net.IP, int, error) {
+	i := strings.LastIndexByte(s, '!')
+	if i < 0 {
+		return nil, 0, fmt.Errorf("invalid address: %s", s)
+	}
+	ip, err := parseIP(s[:i])
+	if err != nil {
+		return nil, 0, err
+	}
+	port, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return nil, 0, err
+	}
+	return ip, port, nil
+}
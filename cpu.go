@@ -9,7 +9,6 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -150,6 +149,17 @@ type CPUTime struct {
 	ChildReal time.Duration
 }
 
+// ReadCPUTime reads the times of the calling process from /dev/cputime.
+func ReadCPUTime(ctx context.Context, opts ...Option) (*CPUTime, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "/dev/cputime")
+	var t CPUTime
+	if err := readCPUTime(file, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 // CPUStats emulates Linux's /proc/stat.
 type CPUStats struct {
 	User time.Duration
@@ -164,42 +174,14 @@ func ReadCPUStats(ctx context.Context, opts ...Option) (*CPUStats, error) {
 		return nil, err
 	}
 
-	dir := filepath.Join(cfg.rootdir, "/proc")
-	d, err := os.Open(dir)
-	if err != nil {
-		return nil, err
-	}
-	defer d.Close()
-
-	names, err := d.Readdirnames(0)
+	procs, err := readProcs(filepath.Join(cfg.rootdir, "/proc"))
 	if err != nil {
 		return nil, err
 	}
-	var up uint32parser
-	pids := make([]uint32, len(names))
-	for i, s := range names {
-		if s == "trace" {
-			continue
-		}
-		pids[i] = up.Parse(s)
-	}
-	if err := up.err; err != nil {
-		return nil, err
-	}
-	sort.Slice(pids, func(i, j int) bool {
-		return pids[i] < pids[j]
-	})
-
 	var stat CPUStats
-	for _, pid := range pids {
-		s := strconv.FormatUint(uint64(pid), 10)
-		file := filepath.Join(dir, s, "status")
-		var p ProcStatus
-		if err := readProcStatus(file, &p); err != nil {
-			return nil, err
-		}
-		stat.User += p.Times.User
-		stat.Sys += p.Times.Sys
+	for _, p := range procs {
+		stat.User += p.Status.Times.User
+		stat.Sys += p.Status.Times.Sys
 	}
 
 	var t Time
@@ -216,9 +198,6 @@ func ReadCPUStats(ctx context.Context, opts ...Option) (*CPUStats, error) {
 func readProcStatus(file string, p *ProcStatus) error {
 	b, err := ioutil.ReadFile(file)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
 		return err
 	}
 	fields := strings.Fields(string(b))
@@ -274,6 +253,25 @@ func readTime(file string, t *Time) error {
 	return nil
 }
 
+func readCPUTime(file string, t *CPUTime) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) != 6 {
+		return errors.New("invalid format")
+	}
+	var up uint32parser
+	t.User = time.Duration(up.Parse(fields[0])) * time.Millisecond
+	t.Sys = time.Duration(up.Parse(fields[1])) * time.Millisecond
+	t.Real = time.Duration(up.Parse(fields[2])) * time.Millisecond
+	t.ChildUser = time.Duration(up.Parse(fields[3])) * time.Millisecond
+	t.ChildSys = time.Duration(up.Parse(fields[4])) * time.Millisecond
+	t.ChildReal = time.Duration(up.Parse(fields[5])) * time.Millisecond
+	return up.err
+}
+
 type uint32parser struct {
 	err error
 }
@@ -44,3 +44,17 @@ func TestReadTime(t *testing.T) {
 		t.Errorf("ReadCPUTime: %v", cmp.Diff(want, stat))
 	}
 }
+
+func TestReadCPUTime(t *testing.T) {
+	ctx := context.Background()
+	stat, err := ReadCPUTime(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &CPUTime{
+		Real: 30 * time.Millisecond,
+	}
+	if !cmp.Equal(want, stat) {
+		t.Errorf("ReadCPUTime: %v", cmp.Diff(want, stat))
+	}
+}
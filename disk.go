@@ -4,27 +4,148 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // Storage represents /dev/sdXX/ctl.
 type Storage struct {
 	Name       string
 	Model      string
-	Capacity   int64
+	Serial     string
+	Firmware   string
+	Capacity   int64 // in byte
+	SectorSize int64 // in byte
+	Sectors    int64 // number of sectors
 	Partitions []*Partition
+	Controller *Controller
+	IO         DiskIO
+
+	Transport string   // type of the controller, such as ata, ahci, nvme, virtio and usb
+	Link      string   // negotiated link speed, such as sataiii
+	Features  []string // features reported by the driver, such as lba and ncq
+}
+
+// DiskIO represents interrupt counters of a drive.
+type DiskIO struct {
+	Reads  int64
+	Writes int64
+	Cmds   int64
 }
 
 // Partition represents a part of /dev/sdXX/ctl.
 type Partition struct {
-	Name  string
-	Start uint64
-	End   uint64
+	Name       string
+	Start      uint64 // in sectors
+	End        uint64 // in sectors; exclusive
+	SectorSize int64  // in byte
 }
 
-func ReadStorages(ctx context.Context, opts ...Option) ([]*Storage, error) {
+// Offset returns the offset of p in byte.
+func (p *Partition) Offset() int64 {
+	return int64(p.Start) * p.SectorSize
+}
+
+// Size returns the size of p in byte.
+func (p *Partition) Size() int64 {
+	return int64(p.End-p.Start) * p.SectorSize
+}
+
+// contains reports whether q is placed within p.
+func (p *Partition) contains(q *Partition) bool {
+	return p.Start <= q.Start && q.End <= p.End
+}
+
+// overlaps reports whether p and q share any sectors.
+func (p *Partition) overlaps(q *Partition) bool {
+	return p.Start < q.End && q.Start < p.End
+}
+
+// PartitionNode is a node of the partition tree.
+type PartitionNode struct {
+	*Partition
+	Children []*PartitionNode
+}
+
+// Tree returns partitions of s nested by their ranges;
+// for example, fossil, swap, nvram and 9fat are placed under plan9, and plan9 is placed under data.
+// A partition is placed under the smallest partition that contains it.
+// If a partition is out of s, or partially overlaps another partition,
+// Tree returns the tree and an error that reports them.
+func (s *Storage) Tree() ([]*PartitionNode, error) {
+	nodes := make([]*PartitionNode, len(s.Partitions))
+	for i, p := range s.Partitions {
+		nodes[i] = &PartitionNode{Partition: p}
+	}
+	var (
+		roots []*PartitionNode
+		errs  []error
+	)
+	for i, n := range nodes {
+		if n.Start > n.End {
+			errs = append(errs, fmt.Errorf("%s: start %d is greater than end %d", n.Name, n.Start, n.End))
+		}
+		if s.Sectors > 0 && n.End > uint64(s.Sectors) {
+			errs = append(errs, fmt.Errorf("%s: end %d exceeds %d sectors", n.Name, n.End, s.Sectors))
+		}
+		var parent *PartitionNode
+		for j, m := range nodes {
+			if i == j || !m.contains(n.Partition) {
+				continue
+			}
+			// a partition that has the same range is a parent if it comes first.
+			if n.contains(m.Partition) && j > i {
+				continue
+			}
+			if parent == nil || parent.contains(m.Partition) {
+				parent = m
+			}
+		}
+		if parent == nil {
+			roots = append(roots, n)
+		} else {
+			parent.Children = append(parent.Children, n)
+		}
+	}
+	errs = append(errs, checkOverlaps(roots)...)
+	return roots, errors.Join(errs...)
+}
+
+// checkOverlaps reports partitions that overlap each other in siblings, recursively.
+func checkOverlaps(nodes []*PartitionNode) []error {
+	var errs []error
+	for i, n := range nodes {
+		for _, m := range nodes[i+1:] {
+			if n.overlaps(m.Partition) {
+				errs = append(errs, fmt.Errorf("%s and %s overlap", n.Name, m.Name))
+			}
+		}
+		errs = append(errs, checkOverlaps(n.Children)...)
+	}
+	return errs
+}
+
+// Controller represents a line of /dev/sdctl.
+type Controller struct {
+	Name         string // such as sdC
+	Type         string // such as ata, ahci and nvme
+	Port         uint64 // I/O port
+	CtlPort      uint64 // control port
+	IRQ          int
+	IntrOK       int64 // interrupts handled
+	IntrBusy     int64 // interrupts while the controller is busy
+	IntrNilDrive int64 // interrupts without the drive
+
+	Flags  []string          // words without values, such as capabilities of ahci
+	Others map[string]string // other key/value pairs, and pairs that failed to parse
+}
+
+// ReadControllers reads storage controllers from /dev/sdctl.
+func ReadControllers(ctx context.Context, opts ...Option) ([]*Controller, error) {
 	cfg := newConfig(opts...)
 	sdctl := filepath.Join(cfg.rootdir, "/dev/sdctl")
 	f, err := os.Open(sdctl)
@@ -33,37 +154,101 @@ func ReadStorages(ctx context.Context, opts ...Option) ([]*Storage, error) {
 	}
 	defer f.Close()
 
-	var a []*Storage
+	var a []*Controller
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
-		fields := bytes.Split(scanner.Bytes(), delim)
-		if len(fields) == 0 {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "sd") {
 			continue
 		}
-		exp := string(fields[0]) + "*"
-		if !strings.HasPrefix(exp, "sd") {
+		a = append(a, parseController(fields))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseController parses fields formed as "name type key value...".
+// Drivers may also print words without values, such as "ncq" in ahci;
+// parseController treats a word as a key only if it is followed by a number.
+func parseController(fields []string) *Controller {
+	c := Controller{
+		Name:   fields[0],
+		Others: make(map[string]string),
+	}
+	var a []string
+	if len(fields) > 1 {
+		c.Type = fields[1]
+		a = fields[2:]
+	}
+	for i := 0; i < len(a); i++ {
+		key := a[i]
+		if i+1 >= len(a) || !isControllerValue(key, a[i+1]) {
+			c.Flags = append(c.Flags, key)
 			continue
 		}
-		dir := filepath.Join(cfg.rootdir, "/dev", exp)
+		i++
+		v := strings.TrimSuffix(a[i], ":")
+		var p intParser
+		switch key {
+		case "port":
+			c.Port = p.ParseUint64(strings.TrimPrefix(v, "0x"), 16)
+		case "ctl":
+			c.CtlPort = p.ParseUint64(strings.TrimPrefix(v, "0x"), 16)
+		case "irq":
+			c.IRQ = p.ParseInt(v, 10)
+		case "intr-ok":
+			c.IntrOK = p.ParseInt64(v, 10)
+		case "intr-busy":
+			c.IntrBusy = p.ParseInt64(v, 10)
+		case "intr-nil-drive":
+			c.IntrNilDrive = p.ParseInt64(v, 10)
+		default:
+			c.Others[key] = v
+		}
+		if p.Err() != nil {
+			c.Others[key] = v
+		}
+	}
+	return &c
+}
+
+// isControllerValue reports whether v is the value of key in /dev/sdctl.
+func isControllerValue(key, v string) bool {
+	switch key {
+	case "port", "ctl", "irq", "intr-ok", "intr-busy", "intr-nil-drive":
+		return true
+	}
+	return v != "" && v[0] >= '0' && v[0] <= '9'
+}
+
+func ReadStorages(ctx context.Context, opts ...Option) ([]*Storage, error) {
+	cfg := newConfig(opts...)
+	ctlrs, err := ReadControllers(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var a []*Storage
+	for _, c := range ctlrs {
+		dir := filepath.Join(cfg.rootdir, "/dev", c.Name+"*")
 		m, err := filepath.Glob(dir)
 		if err != nil {
 			return nil, err
 		}
 		for _, dir := range m {
-			s, err := readStorage(dir)
+			s, err := readStorage(dir, c)
 			if err != nil {
 				return nil, err
 			}
 			a = append(a, s)
 		}
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
 	return a, nil
 }
 
-func readStorage(dir string) (*Storage, error) {
+func readStorage(dir string, c *Controller) (*Storage, error) {
 	ctl := filepath.Join(dir, "ctl")
 	f, err := os.Open(ctl)
 	if err != nil {
@@ -71,8 +256,11 @@ func readStorage(dir string) (*Storage, error) {
 	}
 	defer f.Close()
 
-	var s Storage
-	s.Name = filepath.Base(dir)
+	s := Storage{
+		Name:       filepath.Base(dir),
+		Controller: c,
+		Transport:  c.Type,
+	}
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := scanner.Bytes()
@@ -91,6 +279,12 @@ func readStorage(dir string) (*Storage, error) {
 				return nil, err
 			}
 			s.Capacity = sec * size
+			s.SectorSize = size
+			s.Sectors = sec
+		case bytes.HasPrefix(line, []byte("interrupts ")):
+			if err := parseDiskIO(string(line), &s.IO); err != nil {
+				return nil, err
+			}
 		case bytes.HasPrefix(line, []byte("part ")):
 			fields := bytes.Split(line, delim)
 			if len(fields) < 4 {
@@ -107,10 +301,130 @@ func readStorage(dir string) (*Storage, error) {
 				Start: start,
 				End:   end,
 			})
+		default:
+			parse, ok := ctlParsers[s.Transport]
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(string(line))
+			if len(fields) == 0 {
+				continue
+			}
+			parse(&s, fields[0], fields[1:])
 		}
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	for _, p := range s.Partitions {
+		p.SectorSize = s.SectorSize
+	}
 	return &s, nil
 }
+
+// ctlParsers are parsers of driver-specific lines in /dev/sdXX/ctl, keyed by the type of the controller.
+// Lines from ata and usb storage drivers are handled by readStorage itself.
+// Parsers ignore lines they don't recognize.
+var ctlParsers = map[string]func(s *Storage, key string, fields []string){
+	"ahci":   parseAHCICtl,
+	"nvme":   parseIdentCtl,
+	"virtio": parseIdentCtl,
+}
+
+// parseIdentCtl parses lines that identify the drive, such as "model\tname".
+func parseIdentCtl(s *Storage, key string, fields []string) {
+	switch key {
+	case "model":
+		s.Model = strings.Join(fields, " ")
+	case "serial":
+		s.Serial = strings.Join(fields, " ")
+	case "firm", "firmware":
+		s.Firmware = strings.Join(fields, " ")
+	case "feat", "flag":
+		s.Features = append(s.Features, fields...)
+	}
+}
+
+// parseAHCICtl parses lines reported by the ahci driver in addition to parseIdentCtl.
+// The mode line is formed as "mode\tconfigured negotiated", such as "mode auto sataiii".
+// Mode lines in other forms are ignored.
+func parseAHCICtl(s *Storage, key string, fields []string) {
+	if key != "mode" {
+		parseIdentCtl(s, key, fields)
+		return
+	}
+	if len(fields) == 2 {
+		s.Link = fields[1]
+	}
+}
+
+// parseDiskIO parses a line formed as "interrupts read n write n cmds n".
+func parseDiskIO(s string, io *DiskIO) error {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 1 {
+		return fmt.Errorf("invalid format: %s", s)
+	}
+	var p intParser
+	for i := 1; i < len(fields); i += 2 {
+		v := fields[i+1]
+		switch fields[i] {
+		case "read":
+			io.Reads = p.ParseInt64(v, 10)
+		case "write":
+			io.Writes = p.ParseInt64(v, 10)
+		case "cmds":
+			io.Cmds = p.ParseInt64(v, 10)
+		}
+	}
+	return p.Err()
+}
+
+// DiskIORate represents operations per second of a drive between two samples.
+type DiskIORate struct {
+	Name    string
+	Elapsed time.Duration
+	Reads   float64
+	Writes  float64
+	Cmds    float64
+}
+
+// DiskIOSampler calculates I/O rates of drives from successive samples.
+type DiskIOSampler struct {
+	prev     map[string]DiskIO
+	prevTime time.Time
+}
+
+// Sample records storages read at t, and returns the rates of each drive since the previous sample.
+// Drives that first appear in storages, or their counters are reset, are not contained in the result.
+func (s *DiskIOSampler) Sample(t time.Time, storages []*Storage) []*DiskIORate {
+	var a []*DiskIORate
+	elapsed := t.Sub(s.prevTime)
+	m := make(map[string]DiskIO, len(storages))
+	for _, st := range storages {
+		m[st.Name] = st.IO
+		prev, ok := s.prev[st.Name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		r := DiskIORate{
+			Name:    st.Name,
+			Elapsed: elapsed,
+		}
+		d := DiskIO{
+			Reads:  st.IO.Reads - prev.Reads,
+			Writes: st.IO.Writes - prev.Writes,
+			Cmds:   st.IO.Cmds - prev.Cmds,
+		}
+		if d.Reads < 0 || d.Writes < 0 || d.Cmds < 0 {
+			continue
+		}
+		sec := elapsed.Seconds()
+		r.Reads = float64(d.Reads) / sec
+		r.Writes = float64(d.Writes) / sec
+		r.Cmds = float64(d.Cmds) / sec
+		a = append(a, &r)
+	}
+	s.prev = m
+	s.prevTime = t
+	return a
+}
@@ -2,37 +2,340 @@ package stats
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestReadStorages(t *testing.T) {
 	ctx := context.Background()
+	sdC, sdD := testControllers()
 	disks, err := ReadStorages(ctx, WithRootDir("testdata"))
 	if err != nil {
 		t.Fatal(err)
 	}
 	want := []*Storage{
 		&Storage{
-			Name:     "sdC0",
-			Model:    "QEMU HARDDISK",
-			Capacity: 209715200 * 512, // 100GB
+			Name:       "sdC0",
+			Model:      "QEMU HARDDISK",
+			Capacity:   209715200 * 512, // 100GB
+			SectorSize: 512,
+			Sectors:    209715200,
+			Controller: sdC,
+			Transport:  "ata",
+			IO:         DiskIO{Reads: 2},
 			Partitions: []*Partition{
-				{"data", 0, 209715200},
-				{"plan9", 63, 209712510},
-				{"9fat", 63, 204863},
-				{"nvram", 204863, 204864},
-				{"fossil", 204864, 208663934},
-				{"swap", 208663934, 209712510},
+				{"data", 0, 209715200, 512},
+				{"plan9", 63, 209712510, 512},
+				{"9fat", 63, 204863, 512},
+				{"nvram", 204863, 204864, 512},
+				{"fossil", 204864, 208663934, 512},
+				{"swap", 208663934, 209712510, 512},
 			},
 		},
 		&Storage{
-			Name:  "sdD0",
-			Model: "QEMU    QEMU DVD-ROM    0.12",
+			Name:       "sdD0",
+			Model:      "QEMU    QEMU DVD-ROM    0.12",
+			Controller: sdD,
+			Transport:  "ata",
+			IO:         DiskIO{Reads: 14, Writes: 27, Cmds: 41},
 		},
 	}
 	if !cmp.Equal(want, disks) {
 		t.Errorf("ReadHost: %v", cmp.Diff(want, disks))
 	}
 }
+
+func testControllers() (sdC, sdD *Controller) {
+	sdC = &Controller{
+		Name:         "sdC",
+		Type:         "ata",
+		Port:         0x1f0,
+		CtlPort:      0x3f4,
+		IRQ:          14,
+		IntrOK:       2,
+		IntrBusy:     0,
+		IntrNilDrive: 0,
+		Others:       map[string]string{},
+	}
+	sdD = &Controller{
+		Name:         "sdD",
+		Type:         "ata",
+		Port:         0x170,
+		CtlPort:      0x374,
+		IRQ:          15,
+		IntrOK:       25,
+		IntrBusy:     0,
+		IntrNilDrive: 0,
+		Others:       map[string]string{},
+	}
+	return
+}
+
+func TestReadControllers(t *testing.T) {
+	ctx := context.Background()
+	ctlrs, err := ReadControllers(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdC, sdD := testControllers()
+	want := []*Controller{sdC, sdD}
+	if !cmp.Equal(want, ctlrs) {
+		t.Errorf("ReadControllers: %v", cmp.Diff(want, ctlrs))
+	}
+}
+
+func TestParseController(t *testing.T) {
+	tests := []struct {
+		s    string
+		want *Controller
+	}{
+		{
+			s: "sdE ahci port 0xfebf1000: 64a ncq alp led clo pmb slum pslum iss 3 ncs 31 np 6 ghc 80000002 isr 0 pi 3f 0-5 ver 10300",
+			want: &Controller{
+				Name:  "sdE",
+				Type:  "ahci",
+				Port:  0xfebf1000,
+				Flags: []string{"64a", "ncq", "alp", "led", "clo", "pmb", "slum", "pslum", "0-5"},
+				Others: map[string]string{
+					"iss": "3",
+					"ncs": "31",
+					"np":  "6",
+					"ghc": "80000002",
+					"isr": "0",
+					"pi":  "3f",
+					"ver": "10300",
+				},
+			},
+		},
+		{
+			s: "sdC ata port 1F0 ctl 3F4 irq none intr-ok ?",
+			want: &Controller{
+				Name:    "sdC",
+				Type:    "ata",
+				Port:    0x1f0,
+				CtlPort: 0x3f4,
+				Others: map[string]string{
+					"irq":     "none",
+					"intr-ok": "?",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		c := parseController(strings.Fields(tt.s))
+		if !cmp.Equal(tt.want, c) {
+			t.Errorf("parseController(%q): %v", tt.s, cmp.Diff(tt.want, c))
+		}
+	}
+}
+
+func TestDiskIOSampler(t *testing.T) {
+	var s DiskIOSampler
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := s.Sample(t0, []*Storage{
+		{Name: "sdC0", IO: DiskIO{Reads: 10, Writes: 5, Cmds: 1}},
+		{Name: "sdD0", IO: DiskIO{Reads: 100}},
+	})
+	if len(a) != 0 {
+		t.Errorf("Sample: got %v at first; want empty", a)
+	}
+	a = s.Sample(t0.Add(2*time.Second), []*Storage{
+		{Name: "sdC0", IO: DiskIO{Reads: 30, Writes: 6, Cmds: 5}},
+		{Name: "sdD0", IO: DiskIO{Reads: 3}}, // reset
+		{Name: "sdE0", IO: DiskIO{Reads: 1}},
+	})
+	want := []*DiskIORate{
+		{Name: "sdC0", Elapsed: 2 * time.Second, Reads: 10, Writes: 0.5, Cmds: 2},
+	}
+	if !cmp.Equal(want, a) {
+		t.Errorf("Sample: %v", cmp.Diff(want, a))
+	}
+}
+
+func TestReadStoragesDrivers(t *testing.T) {
+	tests := []struct {
+		driver string
+		want   *Storage
+	}{
+		{
+			driver: "ahci",
+			want: &Storage{
+				Name:       "sdE0",
+				Model:      "Samsung SSD 860 EVO 500GB",
+				Serial:     "S3Z2NB0K123456A",
+				Firmware:   "RVT02B6Q",
+				Capacity:   976773168 * 512,
+				SectorSize: 512,
+				Sectors:    976773168,
+				Partitions: []*Partition{
+					{"data", 0, 976773168, 512},
+					{"plan9", 2048, 976773134, 512},
+				},
+				IO:        DiskIO{Reads: 1201, Writes: 340, Cmds: 1541},
+				Transport: "ahci",
+				Link:      "sataiii",
+				Features:  []string{"lba", "llba", "smart", "power", "nop", "ncq"},
+			},
+		},
+		{
+			driver: "nvme",
+			want: &Storage{
+				Name:       "sdN0",
+				Model:      "WDC WDS500G2B0C-00PXH0",
+				Serial:     "20123A800123",
+				Firmware:   "211070WD",
+				Capacity:   976773168 * 512,
+				SectorSize: 512,
+				Sectors:    976773168,
+				Partitions: []*Partition{
+					{"data", 0, 976773168, 512},
+				},
+				Transport: "nvme",
+			},
+		},
+		{
+			driver: "virtio",
+			want: &Storage{
+				Name:       "sdF0",
+				Model:      "virtio disk",
+				Capacity:   41943040 * 512,
+				SectorSize: 512,
+				Sectors:    41943040,
+				Partitions: []*Partition{
+					{"data", 0, 41943040, 512},
+				},
+				Transport: "virtio",
+				Features:  []string{"segmax", "blksize", "flush"},
+			},
+		},
+		{
+			driver: "usb",
+			want: &Storage{
+				Name:       "sdU0.0",
+				Model:      "SanDisk Cruzer Blade 1.00",
+				Capacity:   30031872 * 512,
+				SectorSize: 512,
+				Sectors:    30031872,
+				Partitions: []*Partition{
+					{"data", 0, 30031872, 512},
+					{"dos", 32, 30031872, 512},
+				},
+				Transport: "usb",
+			},
+		},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		dir := filepath.Join("testdata", "drivers", tt.driver)
+		disks, err := ReadStorages(ctx, WithRootDir(dir))
+		if err != nil {
+			t.Errorf("%s: %v", tt.driver, err)
+			continue
+		}
+		if len(disks) != 1 {
+			t.Errorf("%s: got %d storages; want 1", tt.driver, len(disks))
+			continue
+		}
+		o := cmpopts.IgnoreFields(Storage{}, "Controller")
+		if !cmp.Equal(tt.want, disks[0], o) {
+			t.Errorf("%s: %v", tt.driver, cmp.Diff(tt.want, disks[0], o))
+		}
+		if c := disks[0].Controller; c == nil || c.Type != tt.driver {
+			t.Errorf("%s: Controller = %v; want type %s", tt.driver, c, tt.driver)
+		}
+	}
+}
+
+func TestParseAHCICtl(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"mode auto sataiii", "sataiii"},
+		{"mode sataiii", ""},
+		{"mode auto sataii sataiii", ""},
+		{"mode", ""},
+	}
+	for _, tt := range tests {
+		var s Storage
+		fields := strings.Fields(tt.s)
+		parseAHCICtl(&s, fields[0], fields[1:])
+		if s.Link != tt.want {
+			t.Errorf("parseAHCICtl(%q): Link = %q; want %q", tt.s, s.Link, tt.want)
+		}
+	}
+}
+
+func TestPartitionSize(t *testing.T) {
+	p := &Partition{"fossil", 204864, 208663934, 512}
+	if n := p.Offset(); n != 204864*512 {
+		t.Errorf("Offset() = %d; want %d", n, 204864*512)
+	}
+	if n := p.Size(); n != (208663934-204864)*512 {
+		t.Errorf("Size() = %d; want %d", n, (208663934-204864)*512)
+	}
+}
+
+func TestStorageTree(t *testing.T) {
+	ctx := context.Background()
+	disks, err := ReadStorages(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := disks[0]
+	roots, err := s.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := s.Partitions
+	want := []*PartitionNode{
+		{
+			Partition: a[0], // data
+			Children: []*PartitionNode{
+				{
+					Partition: a[1], // plan9
+					Children: []*PartitionNode{
+						{Partition: a[2]}, // 9fat
+						{Partition: a[3]}, // nvram
+						{Partition: a[4]}, // fossil
+						{Partition: a[5]}, // swap
+					},
+				},
+			},
+		},
+	}
+	if !cmp.Equal(want, roots) {
+		t.Errorf("Tree: %v", cmp.Diff(want, roots))
+	}
+}
+
+func TestStorageTreeInconsistent(t *testing.T) {
+	s := &Storage{
+		Sectors: 1000,
+		Partitions: []*Partition{
+			{"data", 0, 1000, 512},
+			{"plan9", 100, 900, 512},
+			{"fossil", 100, 500, 512},
+			{"swap", 400, 900, 512},
+			{"dos", 800, 1200, 512},
+		},
+	}
+	roots, err := s.Tree()
+	if err == nil {
+		t.Fatal("Tree: want an error")
+	}
+	want := "dos: end 1200 exceeds 1000 sectors\n" +
+		"data and dos overlap\n" +
+		"fossil and swap overlap"
+	if s := err.Error(); s != want {
+		t.Errorf("Tree: got %q; want %q", s, want)
+	}
+	if len(roots) != 2 {
+		t.Errorf("Tree: got %d roots; want 2", len(roots))
+	}
+}
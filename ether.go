@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+)
+
+// EtherConn represents a conversation in /net/etherN/M.
+type EtherConn struct {
+	ID    int
+	Type  int // packet type, such as 0x800 for IPv4; -1 means all types
+	Stats *InterfaceStats
+	PIDs  []uint32 // processes that open the data file
+}
+
+// ReadEtherConns reads conversations from the interface directory such as /net/ether0.
+// PIDs of them are not set; see SetEtherConnOwners.
+func ReadEtherConns(ctx context.Context, opts ...Option) ([]*EtherConn, error) {
+	cfg := newConfig(opts...)
+	ids, err := readDirIDs(cfg.rootdir)
+	if err != nil {
+		return nil, err
+	}
+	var a []*EtherConn
+	for _, id := range ids {
+		c, err := readEtherConn(ctx, cfg.rootdir, id)
+		if os.IsNotExist(err) {
+			continue // the conversation is removed while reading
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, c)
+	}
+	return a, nil
+}
+
+func readEtherConn(ctx context.Context, dir string, id int) (*EtherConn, error) {
+	dir = filepath.Join(dir, strconv.Itoa(id))
+	s, err := readString(filepath.Join(dir, "type"))
+	if err != nil {
+		return nil, err
+	}
+	t, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := ReadInterfaceStats(ctx, WithRootDir(dir))
+	if err != nil {
+		return nil, err
+	}
+	return &EtherConn{
+		ID:    id,
+		Type:  t,
+		Stats: stats,
+	}, nil
+}
+
+// SetEtherConnOwners sets PIDs of conns in the interface directory dev, such as /net/ether0,
+// to the processes that open the data file in tables.
+func SetEtherConnOwners(conns []*EtherConn, dev string, tables []*FDTable) {
+	for _, c := range conns {
+		c.PIDs = nil
+		data := path.Join(dev, strconv.Itoa(c.ID), "data")
+		for _, t := range tables {
+			for _, fd := range t.FDs {
+				if fd.Path == data {
+					c.PIDs = append(c.PIDs, t.PID)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadEtherConns(t *testing.T) {
+	ctx := context.Background()
+	conns, err := ReadEtherConns(ctx, WithRootDir("testdata/net/ether0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := &InterfaceStats{
+		PacketsReceived: 53415030,
+		PacketsSent:     1168959,
+		Mbps:            1000,
+		Addr:            "525409008379",
+	}
+	want := []*EtherConn{
+		{ID: 0, Type: 0x800, Stats: stats},
+		{ID: 1, Type: -1, Stats: stats},
+	}
+	if !cmp.Equal(want, conns) {
+		t.Errorf("ReadEtherConns: %v", cmp.Diff(want, conns))
+	}
+
+	tables := []*FDTable{
+		{PID: 10, FDs: []*FD{{FD: 3, Path: "/net/ether0/0/data"}}},
+		{PID: 20, FDs: []*FD{{FD: 5, Path: "/net/ether0/1/data"}}},
+		{PID: 30, FDs: []*FD{{FD: 5, Path: "/net/ether1/1/data"}}},
+		{PID: 40, FDs: []*FD{{FD: 3, Path: "/net/ether0/1/ctl"}, {FD: 4, Path: "/net/ether0/1/data"}}},
+	}
+	SetEtherConnOwners(conns, "/net/ether0", tables)
+	if want := []uint32{10}; !cmp.Equal(want, conns[0].PIDs) {
+		t.Errorf("PIDs of ether0/0: %v", cmp.Diff(want, conns[0].PIDs))
+	}
+	if want := []uint32{20, 40}; !cmp.Equal(want, conns[1].PIDs) {
+		t.Errorf("PIDs of ether0/1: %v", cmp.Diff(want, conns[1].PIDs))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -22,6 +23,7 @@ type Host struct {
 	Sysname    string
 	Storages   []*Storage
 	Interfaces []*Interface
+	IPSelfTab  []*IPSelf
 }
 
 // MemStats represents the memory statistics.
@@ -115,8 +117,7 @@ type Interface struct {
 }
 
 const (
-	numEther = 8  // see ether(3)
-	numIpifc = 16 // see ip(3)
+	numEther = 8 // see ether(3)
 )
 
 // ReadInterfaces reads network interfaces from etherN.
@@ -184,12 +185,25 @@ func ReadHost(ctx context.Context, opts ...Option) (*Host, error) {
 			return nil, err
 		}
 		h.Interfaces = append(h.Interfaces, ifaces...)
+
+		tab, err := ReadIPSelfTab(ctx, WithRootDir(netroot))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range tab {
+			e.NetDir = s
+		}
+		h.IPSelfTab = append(h.IPSelfTab, tab...)
 	}
 	return &h, nil
 }
 
 func readSysname(rootdir string) (string, error) {
-	file := filepath.Join(rootdir, "/dev/sysname")
+	return readString(filepath.Join(rootdir, "/dev/sysname"))
+}
+
+// readString returns the content of file without surrounding spaces.
+func readString(file string) (string, error) {
 	b, err := ioutil.ReadFile(file)
 	if err != nil {
 		return "", err
@@ -197,6 +211,50 @@ func readSysname(rootdir string) (string, error) {
 	return string(bytes.TrimSpace(b)), nil
 }
 
+// SystemInfo represents the system information.
+type SystemInfo struct {
+	Sysname    string // /dev/sysname
+	OSVersion  string // /dev/osversion
+	HostOwner  string // /dev/hostowner
+	HostDomain string // /dev/hostdomain; authentication domain
+	User       string // /dev/user; the user of the calling process
+	CPU        CPUType
+	NumCPU     int // number of /dev/sysstat rows
+}
+
+// ReadSystemInfo reads the system information from files in /dev.
+func ReadSystemInfo(ctx context.Context, opts ...Option) (*SystemInfo, error) {
+	cfg := newConfig(opts...)
+	var info SystemInfo
+	m := []struct {
+		name string
+		p    *string
+	}{
+		{"/dev/sysname", &info.Sysname},
+		{"/dev/osversion", &info.OSVersion},
+		{"/dev/hostowner", &info.HostOwner},
+		{"/dev/hostdomain", &info.HostDomain},
+		{"/dev/user", &info.User},
+	}
+	for _, v := range m {
+		s, err := readString(filepath.Join(cfg.rootdir, v.name))
+		if err != nil {
+			return nil, err
+		}
+		*v.p = s
+	}
+	if err := readCPUType(cfg.rootdir, &info.CPU); err != nil {
+		return nil, err
+	}
+	a, err := ReadSysStats(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	info.NumCPU = len(a)
+	return &info, nil
+}
+
+// IPStats represents /net/ipifc/N/status.
 type IPStats struct {
 	ID      int    // number of interface in ipifc dir
 	Device  string // associated physical device
@@ -208,8 +266,11 @@ type IPStats struct {
 	Pktout int64 // packets written
 	Errin  int64 // read errors
 	Errout int64 // write errors
+
+	Iplifcs []*Iplifc // logical interfaces
 }
 
+// Iplifc represents a logical interface of the IP interface.
 type Iplifc struct {
 	IP            net.IP
 	Mask          net.IPMask
@@ -221,3 +282,216 @@ type Iplifc struct {
 type Ipv6rp struct {
 	// TODO(lufia): see ip(2)
 }
+
+// ReadIPStats reads IP interface statistics from ipifc/N/status.
+func ReadIPStats(ctx context.Context, opts ...Option) ([]*IPStats, error) {
+	cfg := newConfig(opts...)
+	ids, err := readDirIDs(filepath.Join(cfg.rootdir, "ipifc"))
+	if err != nil {
+		return nil, err
+	}
+	var a []*IPStats
+	for _, i := range ids {
+		p, err := readIPStats(cfg.rootdir, i)
+		if os.IsNotExist(err) {
+			continue // the interface is removed while reading
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, p)
+	}
+	return a, nil
+}
+
+func readIPStats(netroot string, i int) (*IPStats, error) {
+	file := filepath.Join(netroot, "ipifc", strconv.Itoa(i), "status")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := IPStats{ID: i}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s := scanner.Text()
+		if strings.HasPrefix(s, "\t") {
+			lifc, err := parseIplifc(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			stats.Iplifcs = append(stats.Iplifcs, lifc)
+			continue
+		}
+		if err := parseIPStats(s, &stats); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func parseIPStats(s string, stats *IPStats) error {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 0 {
+		return errors.New("invalid format")
+	}
+	var p intParser
+	for i := 0; i < len(fields); i += 2 {
+		v := fields[i+1]
+		switch fields[i] {
+		case "device":
+			stats.Device = v
+		case "maxtu":
+			stats.MTU = p.ParseInt(v, 10)
+		case "sendra":
+			stats.Sendra6 = uint8(p.ParseInt(v, 10))
+		case "recvra":
+			stats.Recvra6 = uint8(p.ParseInt(v, 10))
+		case "pktin":
+			stats.Pktin = p.ParseInt64(v, 10)
+		case "pktout":
+			stats.Pktout = p.ParseInt64(v, 10)
+		case "errin":
+			stats.Errin = p.ParseInt64(v, 10)
+		case "errout":
+			stats.Errout = p.ParseInt64(v, 10)
+		}
+	}
+	return p.Err()
+}
+
+func parseIplifc(s string) (*Iplifc, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, errors.New("invalid format")
+	}
+	ip, err := parseIP(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	mask, err := parseIPMask(fields[1], ip)
+	if err != nil {
+		return nil, err
+	}
+	netip, err := parseIP(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	var p intParser
+	lifc := Iplifc{
+		IP:            ip,
+		Mask:          mask,
+		Net:           netip,
+		PerfLifetime:  p.ParseInt64(fields[3], 10),
+		ValidLifetime: p.ParseInt64(fields[4], 10),
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &lifc, nil
+}
+
+// parseIP parses s as an IP address.
+// If it is an IPv4 address, parseIP returns 4-byte representation.
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address: %s", s)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4, nil
+	}
+	return ip, nil
+}
+
+// parseIPMask parses s as a mask for ip.
+// Plan 9 represents IPv4 masks as v4-in-v6 prefix length such as /120,
+// therefore parseIPMask converts it to the IPv4 mask if ip is IPv4.
+func parseIPMask(s string, ip net.IP) (net.IPMask, error) {
+	if !strings.HasPrefix(s, "/") {
+		m, err := parseIP(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP mask: %s", s)
+		}
+		return net.IPMask(m), nil
+	}
+	n, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || n > 8*net.IPv6len {
+		return nil, fmt.Errorf("invalid IP mask: %s", s)
+	}
+	if len(ip) == net.IPv4len {
+		const v4InV6PrefixLen = 8 * (net.IPv6len - net.IPv4len)
+		if n < v4InV6PrefixLen {
+			return nil, fmt.Errorf("invalid IPv4 mask: %s", s)
+		}
+		return net.CIDRMask(n-v4InV6PrefixLen, 8*net.IPv4len), nil
+	}
+	return net.CIDRMask(n, 8*net.IPv6len), nil
+}
+
+// IPSelf represents an entry of /net/ipselftab.
+type IPSelf struct {
+	IP     net.IP
+	Ref    int    // reference count
+	Type   string // such as 4u, 4b and 6m
+	NetDir string // network directory such as /net and /net.alt; set by ReadHost
+}
+
+// IsUnicast reports whether e is a unicast address.
+func (e *IPSelf) IsUnicast() bool {
+	return strings.ContainsRune(e.Type, 'u')
+}
+
+// IsBroadcast reports whether e is a broadcast address.
+func (e *IPSelf) IsBroadcast() bool {
+	return strings.ContainsRune(e.Type, 'b')
+}
+
+// IsMulticast reports whether e is a multicast address.
+func (e *IPSelf) IsMulticast() bool {
+	return strings.ContainsRune(e.Type, 'm')
+}
+
+// ReadIPSelfTab reads addresses treated as local from ipselftab.
+func ReadIPSelfTab(ctx context.Context, opts ...Option) ([]*IPSelf, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "ipselftab")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var a []*IPSelf
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%s: invalid format: %s", file, scanner.Text())
+		}
+		ip, err := parseIP(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		ref, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, &IPSelf{
+			IP:   ip,
+			Ref:  ref,
+			Type: fields[2],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -9,6 +10,7 @@ import (
 
 func TestReadHost(t *testing.T) {
 	ctx := context.Background()
+	sdC, sdD := testControllers()
 	h, err := ReadHost(ctx, WithRootDir("testdata"))
 	if err != nil {
 		t.Fatal(err)
@@ -17,21 +19,29 @@ func TestReadHost(t *testing.T) {
 		Sysname: "gnot",
 		Storages: []*Storage{
 			&Storage{
-				Name:     "sdC0",
-				Model:    "QEMU HARDDISK",
-				Capacity: 209715200 * 512, // 100GB
+				Name:       "sdC0",
+				Model:      "QEMU HARDDISK",
+				Capacity:   209715200 * 512, // 100GB
+				SectorSize: 512,
+				Sectors:    209715200,
+				Controller: sdC,
+				Transport:  "ata",
+				IO:         DiskIO{Reads: 2},
 				Partitions: []*Partition{
-					{"data", 0, 209715200},
-					{"plan9", 63, 209712510},
-					{"9fat", 63, 204863},
-					{"nvram", 204863, 204864},
-					{"fossil", 204864, 208663934},
-					{"swap", 208663934, 209712510},
+					{"data", 0, 209715200, 512},
+					{"plan9", 63, 209712510, 512},
+					{"9fat", 63, 204863, 512},
+					{"nvram", 204863, 204864, 512},
+					{"fossil", 204864, 208663934, 512},
+					{"swap", 208663934, 209712510, 512},
 				},
 			},
 			&Storage{
-				Name:  "sdD0",
-				Model: "QEMU    QEMU DVD-ROM    0.12",
+				Name:       "sdD0",
+				Model:      "QEMU    QEMU DVD-ROM    0.12",
+				Controller: sdD,
+				Transport:  "ata",
+				IO:         DiskIO{Reads: 14, Writes: 27, Cmds: 41},
 			},
 		},
 		Interfaces: []*Interface{
@@ -40,6 +50,20 @@ func TestReadHost(t *testing.T) {
 				Addr: "525409008379",
 			},
 		},
+		IPSelfTab: []*IPSelf{
+			{IP: net.IPv4(0, 0, 0, 0).To4(), Ref: 2, Type: "4b", NetDir: "/net"},
+			{IP: net.IPv4(127, 0, 0, 0).To4(), Ref: 1, Type: "4b", NetDir: "/net"},
+			{IP: net.IPv4(127, 0, 0, 1).To4(), Ref: 1, Type: "4u", NetDir: "/net"},
+			{IP: net.IPv4(127, 255, 255, 255).To4(), Ref: 1, Type: "4b", NetDir: "/net"},
+			{IP: net.IPv4(192, 168, 1, 0).To4(), Ref: 1, Type: "4b", NetDir: "/net"},
+			{IP: net.IPv4(192, 168, 1, 23).To4(), Ref: 1, Type: "4u", NetDir: "/net"},
+			{IP: net.IPv4(192, 168, 1, 255).To4(), Ref: 1, Type: "4b", NetDir: "/net"},
+			{IP: net.IPv4(255, 255, 255, 255).To4(), Ref: 2, Type: "4b", NetDir: "/net"},
+			{IP: net.ParseIP("ff02::1"), Ref: 2, Type: "6m", NetDir: "/net"},
+			{IP: net.IPv4(10, 0, 0, 0).To4(), Ref: 1, Type: "4b", NetDir: "/net.alt"},
+			{IP: net.IPv4(10, 0, 0, 5).To4(), Ref: 1, Type: "4u", NetDir: "/net.alt"},
+			{IP: net.IPv4(10, 0, 0, 255).To4(), Ref: 1, Type: "4b", NetDir: "/net.alt"},
+		},
 	}
 	if !cmp.Equal(want, h) {
 		t.Errorf("ReadHost: %v", cmp.Diff(want, h))
@@ -65,3 +89,130 @@ func TestReadMemStats(t *testing.T) {
 		t.Errorf("ReadMemStats: %v", cmp.Diff(want, h))
 	}
 }
+
+func TestReadIPStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadIPStats(ctx, WithRootDir("testdata/net"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*IPStats{
+		&IPStats{
+			ID:     0,
+			Device: "/net/ether0",
+			MTU:    1514,
+			Pktin:  896404,
+			Pktout: 942198,
+			Iplifcs: []*Iplifc{
+				&Iplifc{
+					IP:            net.IPv4(192, 168, 1, 23).To4(),
+					Mask:          net.CIDRMask(24, 32),
+					Net:           net.IPv4(192, 168, 1, 0).To4(),
+					PerfLifetime:  4294967295,
+					ValidLifetime: 4294967295,
+				},
+			},
+		},
+		&IPStats{
+			ID:     1,
+			Device: "/dev/null",
+			MTU:    16384,
+			Iplifcs: []*Iplifc{
+				&Iplifc{
+					IP:            net.IPv4(127, 0, 0, 1).To4(),
+					Mask:          net.CIDRMask(8, 32),
+					Net:           net.IPv4(127, 0, 0, 0).To4(),
+					PerfLifetime:  4294967295,
+					ValidLifetime: 4294967295,
+				},
+			},
+		},
+		&IPStats{
+			ID:     16,
+			Device: "/net/ether1",
+			MTU:    1514,
+			Pktin:  120,
+			Pktout: 98,
+			Iplifcs: []*Iplifc{
+				&Iplifc{
+					IP:            net.IPv4(10, 0, 0, 5).To4(),
+					Mask:          net.CIDRMask(24, 32),
+					Net:           net.IPv4(10, 0, 0, 0).To4(),
+					PerfLifetime:  4294967295,
+					ValidLifetime: 4294967295,
+				},
+			},
+		},
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadIPStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestParseIPMask(t *testing.T) {
+	tests := []struct {
+		s    string
+		ip   net.IP
+		want net.IPMask
+	}{
+		{"/120", net.IPv4(10, 0, 0, 1).To4(), net.CIDRMask(24, 32)},
+		{"/96", net.IPv4(10, 0, 0, 1).To4(), net.CIDRMask(0, 32)},
+		{"/64", net.ParseIP("fe80::1"), net.CIDRMask(64, 128)},
+		{"255.255.0.0", net.IPv4(10, 0, 0, 1).To4(), net.CIDRMask(16, 32)},
+	}
+	for _, tt := range tests {
+		m, err := parseIPMask(tt.s, tt.ip)
+		if err != nil {
+			t.Errorf("parseIPMask(%q): %v", tt.s, err)
+			continue
+		}
+		if !cmp.Equal(tt.want, m) {
+			t.Errorf("parseIPMask(%q): %v", tt.s, cmp.Diff(tt.want, m))
+		}
+	}
+}
+
+func TestReadSystemInfo(t *testing.T) {
+	ctx := context.Background()
+	info, err := ReadSystemInfo(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SystemInfo{
+		Sysname:    "gnot",
+		OSVersion:  "2000",
+		HostOwner:  "bootes",
+		HostDomain: "example.com",
+		User:       "lufia",
+		CPU: CPUType{
+			Name:  "Core i7/Xeon",
+			Clock: 2403,
+		},
+		NumCPU: 2,
+	}
+	if !cmp.Equal(want, info) {
+		t.Errorf("ReadSystemInfo: %v", cmp.Diff(want, info))
+	}
+}
+
+func TestIPSelfType(t *testing.T) {
+	tests := []struct {
+		e                             IPSelf
+		unicast, broadcast, multicast bool
+	}{
+		{IPSelf{Type: "4u"}, true, false, false},
+		{IPSelf{Type: "4b"}, false, true, false},
+		{IPSelf{Type: "6m"}, false, false, true},
+	}
+	for _, tt := range tests {
+		if v := tt.e.IsUnicast(); v != tt.unicast {
+			t.Errorf("IsUnicast(%s) = %t; want %t", tt.e.Type, v, tt.unicast)
+		}
+		if v := tt.e.IsBroadcast(); v != tt.broadcast {
+			t.Errorf("IsBroadcast(%s) = %t; want %t", tt.e.Type, v, tt.broadcast)
+		}
+		if v := tt.e.IsMulticast(); v != tt.multicast {
+			t.Errorf("IsMulticast(%s) = %t; want %t", tt.e.Type, v, tt.multicast)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package ndb parses the network database described in ndb(6).
+package ndb
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Tuple represents an attribute=value pair.
+type Tuple struct {
+	Attr string
+	Val  string
+}
+
+// Record represents a database entry.
+// It consists of a line starting at column zero and following lines starting with white space.
+type Record []Tuple
+
+// Get returns the first value of attr in r.
+// If r doesn't have attr, Get returns an empty string.
+func (r Record) Get(attr string) string {
+	for _, t := range r {
+		if t.Attr == attr {
+			return t.Val
+		}
+	}
+	return ""
+}
+
+// GetAll returns all values of attr in r.
+func (r Record) GetAll(attr string) []string {
+	var a []string
+	for _, t := range r {
+		if t.Attr == attr {
+			a = append(a, t.Val)
+		}
+	}
+	return a
+}
+
+// Has reports whether r has the attr=val pair.
+func (r Record) Has(attr, val string) bool {
+	for _, t := range r {
+		if t.Attr == attr && t.Val == val {
+			return true
+		}
+	}
+	return false
+}
+
+// DB represents a network database.
+type DB struct {
+	Records []Record
+}
+
+// Search returns records that have the attr=val pair.
+func (db *DB) Search(attr, val string) []Record {
+	var a []Record
+	for _, r := range db.Records {
+		if r.Has(attr, val) {
+			a = append(a, r)
+		}
+	}
+	return a
+}
+
+// Open reads and parses the file.
+func Open(file string) (*DB, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	db, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return db, nil
+}
+
+// Parse parses r as a network database.
+func Parse(r io.Reader) (*DB, error) {
+	var (
+		db  DB
+		rec Record
+	)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		s := scanner.Text()
+		a, err := parseLine(s)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if len(a) == 0 {
+			continue
+		}
+		if !strings.HasPrefix(s, " ") && !strings.HasPrefix(s, "\t") {
+			if len(rec) > 0 {
+				db.Records = append(db.Records, rec)
+			}
+			rec = nil
+		}
+		rec = append(rec, a...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rec) > 0 {
+		db.Records = append(db.Records, rec)
+	}
+	return &db, nil
+}
+
+// parseLine parses tuples in s. A word starting with '#' begins a comment.
+func parseLine(s string) ([]Tuple, error) {
+	var a []Tuple
+	for {
+		s = strings.TrimLeft(s, " \t\r")
+		if s == "" || s[0] == '#' {
+			return a, nil
+		}
+		var t Tuple
+		i := strings.IndexAny(s, " \t\r=")
+		if i < 0 {
+			i = len(s)
+		}
+		t.Attr = s[:i]
+		s = s[i:]
+		if strings.HasPrefix(s, "=") {
+			var err error
+			t.Val, s, err = parseValue(s[1:])
+			if err != nil {
+				return nil, err
+			}
+		}
+		a = append(a, t)
+	}
+}
+
+// parseValue parses a value at the beginning of s, and returns it and the rest.
+// The value may be quoted with '"'.
+func parseValue(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.IndexAny(s, " \t\r")
+		if i < 0 {
+			return s, "", nil
+		}
+		return s[:i], s[i:], nil
+	}
+	i := strings.IndexByte(s[1:], '"')
+	if i < 0 {
+		return "", "", fmt.Errorf("unterminated quote: %s", s)
+	}
+	return s[1 : i+1], s[i+2:], nil
+}
@@ -0,0 +1,91 @@
+package ndb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const local = `# local configuration
+database=
+	file=/lib/ndb/local
+	file=/lib/ndb/common
+
+ipnet=home ip=192.168.1.0 ipmask=255.255.255.0
+	ipgw=192.168.1.1
+	dns=192.168.1.1	# router
+	auth=gnot
+
+sys=gnot ip=192.168.1.23 ether=525409008379
+	dom=gnot.example.com
+	proto=il
+sys=term ip=192.168.1.5 comment="file server"
+`
+
+func TestParse(t *testing.T) {
+	db, err := Parse(strings.NewReader(local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DB{
+		Records: []Record{
+			{
+				{"database", ""},
+				{"file", "/lib/ndb/local"},
+				{"file", "/lib/ndb/common"},
+			},
+			{
+				{"ipnet", "home"},
+				{"ip", "192.168.1.0"},
+				{"ipmask", "255.255.255.0"},
+				{"ipgw", "192.168.1.1"},
+				{"dns", "192.168.1.1"},
+				{"auth", "gnot"},
+			},
+			{
+				{"sys", "gnot"},
+				{"ip", "192.168.1.23"},
+				{"ether", "525409008379"},
+				{"dom", "gnot.example.com"},
+				{"proto", "il"},
+			},
+			{
+				{"sys", "term"},
+				{"ip", "192.168.1.5"},
+				{"comment", "file server"},
+			},
+		},
+	}
+	if !cmp.Equal(want, db) {
+		t.Errorf("Parse: %v", cmp.Diff(want, db))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	db, err := Parse(strings.NewReader(local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := db.Search("sys", "gnot")
+	if len(a) != 1 {
+		t.Fatalf("Search: got %d records; want 1", len(a))
+	}
+	if s := a[0].Get("dom"); s != "gnot.example.com" {
+		t.Errorf("Get(dom) = %q; want %q", s, "gnot.example.com")
+	}
+	if s := a[0].Get("ipgw"); s != "" {
+		t.Errorf("Get(ipgw) = %q; want empty", s)
+	}
+	files := db.Records[0].GetAll("file")
+	if want := []string{"/lib/ndb/local", "/lib/ndb/common"}; !cmp.Equal(want, files) {
+		t.Errorf("GetAll(file): %v", cmp.Diff(want, files))
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := Parse(strings.NewReader("sys=a\ncomment=\"broken\n"))
+	if err == nil {
+		t.Errorf("Parse: want an error")
+	}
+}
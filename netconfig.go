@@ -0,0 +1,72 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/lufia/plan9stats/ndb"
+)
+
+// NetConfig represents the runtime IP configuration in /net/ndb.
+type NetConfig struct {
+	IP        net.IP
+	IPMask    net.IPMask
+	IPGateway net.IP
+	DNS       []net.IP
+	Sys       string
+	Dom       string
+}
+
+// ReadNetConfig reads the runtime IP configuration from ndb.
+// It returns a NetConfig for each record that has ip attribute.
+func ReadNetConfig(ctx context.Context, opts ...Option) ([]*NetConfig, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "ndb")
+	db, err := ndb.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	var a []*NetConfig
+	for _, r := range db.Records {
+		if r.Get("ip") == "" {
+			continue
+		}
+		c, err := parseNetConfig(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		a = append(a, c)
+	}
+	return a, nil
+}
+
+func parseNetConfig(r ndb.Record) (*NetConfig, error) {
+	c := NetConfig{
+		Sys: r.Get("sys"),
+		Dom: r.Get("dom"),
+	}
+	var err error
+	if c.IP, err = parseIP(r.Get("ip")); err != nil {
+		return nil, err
+	}
+	if s := r.Get("ipmask"); s != "" {
+		if c.IPMask, err = parseIPMask(s, c.IP); err != nil {
+			return nil, err
+		}
+	}
+	if s := r.Get("ipgw"); s != "" {
+		if c.IPGateway, err = parseIP(s); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range r.GetAll("dns") {
+		ip, err := parseIP(s)
+		if err != nil {
+			return nil, err
+		}
+		c.DNS = append(c.DNS, ip)
+	}
+	return &c, nil
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadNetConfig(t *testing.T) {
+	ctx := context.Background()
+	a, err := ReadNetConfig(ctx, WithRootDir("testdata/net"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*NetConfig{
+		{
+			IP:        net.IPv4(192, 168, 1, 23).To4(),
+			IPMask:    net.CIDRMask(24, 32),
+			IPGateway: net.IPv4(192, 168, 1, 1).To4(),
+			DNS: []net.IP{
+				net.IPv4(192, 168, 1, 1).To4(),
+				net.IPv4(8, 8, 8, 8).To4(),
+			},
+			Sys: "gnot",
+			Dom: "gnot.example.com",
+		},
+	}
+	if !cmp.Equal(want, a) {
+		t.Errorf("ReadNetConfig: %v", cmp.Diff(want, a))
+	}
+}
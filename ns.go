@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NSEntry represents a bind or a mount in /proc/N/ns.
+type NSEntry struct {
+	Op     string // bind or mount
+	Before bool   // -b flag
+	After  bool   // -a flag
+	Create bool   // -c flag
+	Cache  bool   // -C flag
+	Source string // new file for bind, or the channel to a server for mount
+	Target string // old file
+	Spec   string // attach specifier for mount
+}
+
+// Namespace represents /proc/N/ns.
+type Namespace struct {
+	Entries []*NSEntry
+	Dir     string // current working directory
+}
+
+// ReadNamespace reads the namespace of the process pid from /proc/N/ns.
+func ReadNamespace(ctx context.Context, pid uint32, opts ...Option) (*Namespace, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "/proc", strconv.FormatUint(uint64(pid), 10), "ns")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ns Namespace
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		args, err := tokenize(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if len(args) == 0 {
+			continue
+		}
+		switch args[0] {
+		case "cd":
+			if len(args) != 2 {
+				return nil, fmt.Errorf("%s: invalid cd: %s", file, scanner.Text())
+			}
+			ns.Dir = args[1]
+		case "bind", "mount":
+			e, err := parseNSEntry(args)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file, err)
+			}
+			ns.Entries = append(ns.Entries, e)
+		default:
+			return nil, fmt.Errorf("%s: unknown operation: %s", file, args[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &ns, nil
+}
+
+func parseNSEntry(args []string) (*NSEntry, error) {
+	e := NSEntry{Op: args[0]}
+	args = args[1:]
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		for _, c := range args[0][1:] {
+			switch c {
+			case 'a':
+				e.After = true
+			case 'b':
+				e.Before = true
+			case 'c':
+				e.Create = true
+			case 'C':
+				e.Cache = true
+			default:
+				return nil, fmt.Errorf("unknown flag: %c", c)
+			}
+		}
+		args = args[1:]
+	}
+	switch {
+	case e.Op == "bind" && len(args) == 2:
+	case e.Op == "mount" && (len(args) == 2 || len(args) == 3):
+		if len(args) == 3 {
+			e.Spec = args[2]
+		}
+	default:
+		return nil, fmt.Errorf("invalid %s: %q", e.Op, args)
+	}
+	e.Source = args[0]
+	e.Target = args[1]
+	return &e, nil
+}
+
+// tokenize splits s into words as rc(1) does; a quoted word may contain spaces.
+func tokenize(s string) ([]string, error) {
+	var (
+		a      []string
+		w      strings.Builder
+		inWord bool
+		quoted bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '\'':
+			if i+1 < len(s) && s[i+1] == '\'' {
+				w.WriteByte('\'')
+				i++
+			} else {
+				quoted = false
+			}
+		case quoted:
+			w.WriteByte(c)
+		case c == '\'':
+			quoted = true
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				a = append(a, w.String())
+				w.Reset()
+				inWord = false
+			}
+		default:
+			w.WriteByte(c)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote: %s", s)
+	}
+	if inWord {
+		a = append(a, w.String())
+	}
+	return a, nil
+}
+
+// Resolve returns entries that provide the file name.
+// Each of them is either a mount, or a bind of a kernel device such as #I.
+// Since a directory may be a union, Resolve returns them in the order of lookups.
+// If nothing is bound to name, Resolve returns nil.
+func (ns *Namespace) Resolve(name string) []*NSEntry {
+	var a []*NSEntry
+	seen := make(map[*NSEntry]bool)
+	for _, e := range ns.resolve(path.Clean(name), len(ns.Entries)) {
+		if seen[e] {
+			continue
+		}
+		seen[e] = true
+		a = append(a, e)
+	}
+	return a
+}
+
+// resolve resolves name with the first n entries, which were effective
+// when the (n+1)th entry was bound.
+func (ns *Namespace) resolve(name string, n int) []*NSEntry {
+	var (
+		target string
+		union  []int
+	)
+	for i, e := range ns.Entries[:n] {
+		if !hasPathPrefix(name, e.Target) {
+			continue
+		}
+		switch {
+		case len(e.Target) > len(target) || union == nil:
+			target = e.Target
+			union = []int{i}
+		case e.Target != target:
+			// e replaced a parent of target; binds under the old parent are no longer reachable
+			// unless e binds the parent onto itself.
+			if !e.Before && !e.After && e.Source != e.Target {
+				target = e.Target
+				union = []int{i}
+			}
+		case e.Before:
+			union = append([]int{i}, union...)
+		case e.After:
+			union = append(union, i)
+		default:
+			union = []int{i}
+		}
+	}
+	var a []*NSEntry
+	for _, i := range union {
+		e := ns.Entries[i]
+		if e.Op == "mount" || strings.HasPrefix(e.Source, "#") {
+			a = append(a, e)
+			continue
+		}
+		rest := strings.TrimPrefix(name, e.Target)
+		a = append(a, ns.resolve(path.Join(e.Source, rest), i)...)
+	}
+	return a
+}
+
+// hasPathPrefix reports whether name is dir or a file under dir.
+func hasPathPrefix(name, dir string) bool {
+	if dir == "/" || name == dir {
+		return true
+	}
+	return strings.HasPrefix(name, dir+"/")
+}
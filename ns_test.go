@@ -0,0 +1,107 @@
+package stats
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadNamespace(t *testing.T) {
+	ctx := context.Background()
+	ns, err := ReadNamespace(ctx, 72, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Namespace{
+		Entries: []*NSEntry{
+			{Op: "mount", After: true, Cache: true, Source: "#s/boot", Target: "/root"},
+			{Op: "bind", Source: "/", Target: "/"},
+			{Op: "bind", After: true, Source: "#¤", Target: "/dev"},
+			{Op: "bind", Before: true, Source: "#c", Target: "/dev"},
+			{Op: "bind", Source: "/root", Target: "/root"},
+			{Op: "bind", After: true, Source: "/root", Target: "/"},
+			{Op: "bind", Create: true, Source: "/root/mnt", Target: "/mnt"},
+			{Op: "bind", Source: "/amd64/bin", Target: "/bin"},
+			{Op: "bind", After: true, Source: "/rc/bin", Target: "/bin"},
+			{Op: "bind", Source: "#l", Target: "/net"},
+			{Op: "bind", After: true, Source: "#I", Target: "/net"},
+			{Op: "mount", After: true, Source: "#s/cs", Target: "/net"},
+			{Op: "mount", After: true, Source: "#s/dns", Target: "/net"},
+			{Op: "mount", Source: "#s/boot", Target: "/n/dump", Spec: "dump"},
+			{Op: "bind", Create: true, Source: "/usr/web/tmp", Target: "/tmp"},
+		},
+		Dir: "/usr/web",
+	}
+	if !cmp.Equal(want, ns) {
+		t.Errorf("ReadNamespace: %v", cmp.Diff(want, ns))
+	}
+}
+
+func TestNamespaceResolve(t *testing.T) {
+	ctx := context.Background()
+	ns, err := ReadNamespace(ctx, 72, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := ns.Entries
+	tests := []struct {
+		name string
+		want []*NSEntry
+	}{
+		{"/net/tcp", []*NSEntry{e[9], e[10], e[11], e[12]}},
+		{"/dev/sysstat", []*NSEntry{e[3], e[2]}},
+		{"/bin/ls", []*NSEntry{e[0]}},
+		{"/n/dump/2024", []*NSEntry{e[13]}},
+		{"/tmp/a", []*NSEntry{e[0]}},
+		{"/", []*NSEntry{e[0]}},
+	}
+	for _, tt := range tests {
+		a := ns.Resolve(tt.name)
+		if !cmp.Equal(tt.want, a) {
+			t.Errorf("Resolve(%q): %v", tt.name, cmp.Diff(tt.want, a))
+		}
+	}
+
+	// A later bind onto a parent hides earlier binds under it.
+	x := &NSEntry{Op: "bind", Source: "#x", Target: "/net/tcp"}
+	y := &NSEntry{Op: "bind", Source: "#y", Target: "/net"}
+	z := &NSEntry{Op: "bind", Source: "#z", Target: "/net/tcp"}
+	replaced := []struct {
+		entries []*NSEntry
+		want    []*NSEntry
+	}{
+		{[]*NSEntry{x, y}, []*NSEntry{y}},
+		{[]*NSEntry{y, x}, []*NSEntry{x}},
+		{[]*NSEntry{x, y, z}, []*NSEntry{z}},
+	}
+	for _, tt := range replaced {
+		ns := &Namespace{Entries: tt.entries}
+		a := ns.Resolve("/net/tcp/0")
+		if !cmp.Equal(tt.want, a) {
+			t.Errorf("Resolve(%q) with %d entries: %v", "/net/tcp/0", len(tt.entries), cmp.Diff(tt.want, a))
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"bind  / /", []string{"bind", "/", "/"}},
+		{"mount -a '#s/cs' /net ", []string{"mount", "-a", "#s/cs", "/net"}},
+		{"bind '/a b' '/it''s'", []string{"bind", "/a b", "/it's"}},
+		{"mount '#s/boot' /root ''", []string{"mount", "#s/boot", "/root", ""}},
+	}
+	for _, tt := range tests {
+		a, err := tokenize(tt.s)
+		if err != nil {
+			t.Errorf("tokenize(%q): %v", tt.s, err)
+			continue
+		}
+		if !cmp.Equal(tt.want, a) {
+			t.Errorf("tokenize(%q): %v", tt.s, cmp.Diff(tt.want, a))
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+const (
+	mbrSignature  = 0xaa55
+	mbrTypeGPT    = 0xee
+	mbrTypePlan9  = 0x39
+	maxLogicalMBR = 128 // limit of logical partitions to avoid loops in the EBR chain
+
+	gptSignature  = "EFI PART"
+	maxGPTEntries = 4 << 20 // limit of the total size of GPT entries in byte
+
+	minSectorSize = 512
+)
+
+// mbrNames maps MBR partition types to names of partitions.
+var mbrNames = map[byte]string{
+	0x01: "dos", // FAT12
+	0x04: "dos", // FAT16
+	0x06: "dos", // FAT16
+	0x0b: "dos", // FAT32
+	0x0c: "dos", // FAT32 LBA
+	0x0e: "dos", // FAT16 LBA
+	0x07: "ntfs",
+	0x39: "plan9",
+	0x82: "linuxswap",
+	0x83: "linux",
+}
+
+var gptTypePlan9 = parseGUID("C91818F9-8025-47AF-89D2-F030D7000C2C")
+
+// gptNames maps GPT partition type GUIDs to names of partitions.
+var gptNames = map[[16]byte]string{
+	gptTypePlan9: "plan9",
+	parseGUID("C12A7328-F81F-11D2-BA4B-00A0C93EC93B"): "esp",
+	parseGUID("EBD0A0A2-B9E5-4433-87C0-68B6B72699C7"): "dos",
+	parseGUID("0FC63DAF-8483-4772-8E79-3D69D8477DE4"): "linux",
+	parseGUID("0657FD6D-A4AB-43C4-84E5-0933C84B4F4F"): "linuxswap",
+}
+
+// parseGUID parses s formed as "XXXXXXXX-XXXX-XXXX-XXXX-XXXXXXXXXXXX" into the byte order stored in GPT,
+// that is the first three fields are little-endian. It panics if s is invalid.
+func parseGUID(s string) [16]byte {
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil || len(b) != 16 {
+		panic("invalid GUID: " + s)
+	}
+	var g [16]byte
+	g[0], g[1], g[2], g[3] = b[3], b[2], b[1], b[0]
+	g[4], g[5] = b[5], b[4]
+	g[6], g[7] = b[7], b[6]
+	copy(g[8:], b[8:])
+	return g
+}
+
+// ReadPartitions reads partition tables from r, such as /dev/sdC0/data or a disk image,
+// that has size bytes and consists of sectors of sectorSize bytes.
+//
+// It decodes MBR, including logical partitions, or GPT,
+// and then the Plan 9 partition table stored in the plan9 partition.
+// Like partitions in /dev/sdXX/ctl, the result starts with the data partition,
+// that covers the whole disk, and each Plan 9 partition follows the plan9 partition.
+func ReadPartitions(r io.ReaderAt, size, sectorSize int64) ([]*Partition, error) {
+	if sectorSize < minSectorSize {
+		return nil, fmt.Errorf("invalid sector size: %d", sectorSize)
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("invalid size: %d", size)
+	}
+	t := partitionTable{
+		r:          r,
+		sectorSize: sectorSize,
+		names:      make(map[string]int),
+	}
+	t.add("data", 0, uint64(size/sectorSize))
+
+	sec, err := t.readSector(0)
+	if err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint16(sec[510:]) != mbrSignature {
+		return t.parts, nil
+	}
+	if sec[446+4] == mbrTypeGPT {
+		err = t.readGPT()
+	} else {
+		err = t.readMBR(sec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.parts, nil
+}
+
+type partitionTable struct {
+	r          io.ReaderAt
+	sectorSize int64
+	parts      []*Partition
+	names      map[string]int
+}
+
+func (t *partitionTable) readSector(lba uint64) ([]byte, error) {
+	b := make([]byte, t.sectorSize)
+	if err := t.readAt(b, lba); err != nil {
+		return nil, fmt.Errorf("read sector %d: %w", lba, err)
+	}
+	return b, nil
+}
+
+// readAt reads len(b) bytes from the sector lba.
+// Unlike io.ReaderAt, it doesn't return io.EOF if b is fully read.
+func (t *partitionTable) readAt(b []byte, lba uint64) error {
+	if lba > math.MaxInt64/uint64(t.sectorSize) {
+		return fmt.Errorf("sector %d is out of range", lba)
+	}
+	n, err := t.r.ReadAt(b, int64(lba)*t.sectorSize)
+	if err == io.EOF && n == len(b) {
+		err = nil
+	}
+	return err
+}
+
+// add appends a partition. If name is already used, add renames it to name.N.
+func (t *partitionTable) add(name string, start, end uint64) *Partition {
+	if n := t.names[name]; n > 0 {
+		t.names[name]++
+		name = fmt.Sprintf("%s.%d", name, n)
+	} else {
+		t.names[name] = 1
+	}
+	p := &Partition{
+		Name:       name,
+		Start:      start,
+		End:        end,
+		SectorSize: t.sectorSize,
+	}
+	t.parts = append(t.parts, p)
+	return p
+}
+
+type mbrEntry struct {
+	Type  byte
+	Start uint64
+	Count uint64
+}
+
+func parseMBREntries(sec []byte) []mbrEntry {
+	a := make([]mbrEntry, 4)
+	for i := range a {
+		b := sec[446+16*i:]
+		a[i] = mbrEntry{
+			Type:  b[4],
+			Start: uint64(binary.LittleEndian.Uint32(b[8:])),
+			Count: uint64(binary.LittleEndian.Uint32(b[12:])),
+		}
+	}
+	return a
+}
+
+func isExtended(typ byte) bool {
+	return typ == 0x05 || typ == 0x0f || typ == 0x85
+}
+
+func (t *partitionTable) readMBR(sec []byte) error {
+	for i, e := range parseMBREntries(sec) {
+		switch {
+		case e.Type == 0 || e.Count == 0:
+			continue
+		case isExtended(e.Type):
+			if err := t.readEBR(e.Start); err != nil {
+				return err
+			}
+		default:
+			if err := t.addMBR(e, 0, i+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readEBR reads logical partitions in the extended partition at base.
+func (t *partitionTable) readEBR(base uint64) error {
+	lba := base
+	for i := 0; i < maxLogicalMBR; i++ {
+		sec, err := t.readSector(lba)
+		if err != nil {
+			return err
+		}
+		if binary.LittleEndian.Uint16(sec[510:]) != mbrSignature {
+			return fmt.Errorf("sector %d: invalid EBR signature", lba)
+		}
+		a := parseMBREntries(sec)
+		if a[0].Type != 0 && a[0].Count != 0 {
+			if err := t.addMBR(a[0], lba, 5+i); err != nil {
+				return err
+			}
+		}
+		if !isExtended(a[1].Type) || a[1].Count == 0 {
+			return nil
+		}
+		lba = base + a[1].Start
+	}
+	return errors.New("too many logical partitions")
+}
+
+// addMBR appends the partition e that is relative to base.
+// If e is a plan9 partition, addMBR also appends partitions in its Plan 9 partition table.
+func (t *partitionTable) addMBR(e mbrEntry, base uint64, n int) error {
+	name, ok := mbrNames[e.Type]
+	if !ok {
+		name = fmt.Sprintf("p%d", n)
+	}
+	start := base + e.Start
+	p := t.add(name, start, start+e.Count)
+	if e.Type == mbrTypePlan9 {
+		return t.readPlan9(p)
+	}
+	return nil
+}
+
+func (t *partitionTable) readGPT() error {
+	h, err := t.readSector(1)
+	if err != nil {
+		return err
+	}
+	if string(h[:8]) != gptSignature {
+		return errors.New("invalid GPT signature")
+	}
+	lba := binary.LittleEndian.Uint64(h[72:])
+	n := binary.LittleEndian.Uint32(h[80:])
+	size := binary.LittleEndian.Uint32(h[84:])
+	if size < 128 || int64(size) > t.sectorSize {
+		return fmt.Errorf("invalid size of GPT entry: %d", size)
+	}
+	if int64(n)*int64(size) > maxGPTEntries {
+		return fmt.Errorf("too many GPT entries: %d", n)
+	}
+	b := make([]byte, int64(n)*int64(size))
+	if err := t.readAt(b, lba); err != nil {
+		return fmt.Errorf("read GPT entries: %w", err)
+	}
+	var zero [16]byte
+	for i := 0; i < int(n); i++ {
+		e := b[i*int(size):]
+		if bytes.Equal(e[:16], zero[:]) {
+			continue
+		}
+		first := binary.LittleEndian.Uint64(e[32:])
+		last := binary.LittleEndian.Uint64(e[40:])
+		if first > last {
+			return fmt.Errorf("GPT entry %d: first sector %d is after the last sector %d", i, first, last)
+		}
+		var typ [16]byte
+		copy(typ[:], e[:16])
+		p := t.add(gptName(typ, decodeUTF16(e[56:128]), i+1), first, last+1)
+		if typ == gptTypePlan9 {
+			if err := t.readPlan9(p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gptName returns the name of the partition that has typ and label.
+// Since lines of /dev/sdXX/ctl are separated by spaces, spaces and control characters in label are replaced with "_".
+func gptName(typ [16]byte, label string, n int) string {
+	if name, ok := gptNames[typ]; ok {
+		return name
+	}
+	if label == "" {
+		return fmt.Sprintf("p%d", n)
+	}
+	return strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) || !unicode.IsPrint(c) {
+			return '_'
+		}
+		return c
+	}, label)
+}
+
+// decodeUTF16 decodes NUL terminated UTF-16LE string in b.
+func decodeUTF16(b []byte) string {
+	var a []uint16
+	for i := 0; i+1 < len(b); i += 2 {
+		c := binary.LittleEndian.Uint16(b[i:])
+		if c == 0 {
+			break
+		}
+		a = append(a, c)
+	}
+	return string(utf16.Decode(a))
+}
+
+// readPlan9 reads the Plan 9 partition table, that is stored in the second sector of p.
+// Each line of it is formed as "part name start end", relative to p.
+func (t *partitionTable) readPlan9(p *Partition) error {
+	sec, err := t.readSector(p.Start + 1)
+	if err != nil {
+		return err
+	}
+	if i := bytes.IndexByte(sec, 0); i >= 0 {
+		sec = sec[:i]
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(sec))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 4 && fields[0] == "part" {
+			fields = fields[1:]
+		}
+		if len(fields) != 3 {
+			break
+		}
+		var ip intParser
+		start := ip.ParseUint64(fields[1], 10)
+		end := ip.ParseUint64(fields[2], 10)
+		if ip.Err() != nil || start > end || p.Start+end > p.End {
+			break // it's not a partition table
+		}
+		t.add(fields[0], p.Start+start, p.Start+end)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,240 @@
+package stats
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// sparseDisk is a disk image that consists of sectors; unwritten sectors are filled with zero.
+type sparseDisk struct {
+	size    int64
+	sectors map[int64][]byte
+}
+
+func newSparseDisk(size int64) *sparseDisk {
+	return &sparseDisk{size: size, sectors: make(map[int64][]byte)}
+}
+
+func (d *sparseDisk) sector(lba int64) []byte {
+	b, ok := d.sectors[lba]
+	if !ok {
+		b = make([]byte, 512)
+		d.sectors[lba] = b
+	}
+	return b
+}
+
+func (d *sparseDisk) ReadAt(p []byte, off int64) (int, error) {
+	if off >= d.size {
+		return 0, io.EOF
+	}
+	for i := range p {
+		o := off + int64(i)
+		if o >= d.size {
+			return i, io.EOF
+		}
+		p[i] = 0
+		if b, ok := d.sectors[o/512]; ok {
+			p[i] = b[o%512]
+		}
+	}
+	return len(p), nil
+}
+
+func putMBREntry(sec []byte, i int, typ byte, start, count uint32) {
+	b := sec[446+16*i:]
+	b[4] = typ
+	binary.LittleEndian.PutUint32(b[8:], start)
+	binary.LittleEndian.PutUint32(b[12:], count)
+	binary.LittleEndian.PutUint16(sec[510:], 0xaa55)
+}
+
+func TestReadPartitionsMBR(t *testing.T) {
+	const sectors = 209715200
+	d := newSparseDisk(sectors * 512)
+	putMBREntry(d.sector(0), 0, 0x39, 63, 209712510-63)
+	copy(d.sector(64), "part 9fat 0 204800\n"+
+		"part nvram 204800 204801\n"+
+		"part fossil 204801 208663871\n"+
+		"part swap 208663871 209712447\n")
+
+	parts, err := ReadPartitions(d, d.size, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// these should be same as the kernel view in testdata/dev/sdC0/ctl.
+	ctx := context.Background()
+	disks, err := ReadStorages(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := disks[0].Partitions; !cmp.Equal(want, parts) {
+		t.Errorf("ReadPartitions: %v", cmp.Diff(want, parts))
+	}
+}
+
+func TestReadPartitionsLogical(t *testing.T) {
+	d := newSparseDisk(10000 * 512)
+	putMBREntry(d.sector(0), 0, 0x0c, 2048, 1000)
+	putMBREntry(d.sector(0), 1, 0x05, 4000, 6000)
+	putMBREntry(d.sector(4000), 0, 0x83, 1, 999)
+	putMBREntry(d.sector(4000), 1, 0x05, 1000, 2000)
+	putMBREntry(d.sector(5000), 0, 0x0b, 1, 1999)
+
+	parts, err := ReadPartitions(d, d.size, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Partition{
+		{"data", 0, 10000, 512},
+		{"dos", 2048, 3048, 512},
+		{"linux", 4001, 5000, 512},
+		{"dos.1", 5001, 7000, 512},
+	}
+	if !cmp.Equal(want, parts) {
+		t.Errorf("ReadPartitions: %v", cmp.Diff(want, parts))
+	}
+}
+
+func putGPTEntry(b []byte, typ string, first, last uint64, name string) {
+	g := parseGUID(typ)
+	copy(b, g[:])
+	binary.LittleEndian.PutUint64(b[32:], first)
+	binary.LittleEndian.PutUint64(b[40:], last)
+	for i, c := range utf16.Encode([]rune(name)) {
+		binary.LittleEndian.PutUint16(b[56+2*i:], c)
+	}
+}
+
+func TestReadPartitionsGPT(t *testing.T) {
+	d := newSparseDisk(100000 * 512)
+	putMBREntry(d.sector(0), 0, 0xee, 1, 99999)
+	h := d.sector(1)
+	copy(h, "EFI PART")
+	binary.LittleEndian.PutUint64(h[72:], 2)
+	binary.LittleEndian.PutUint32(h[80:], 8)
+	binary.LittleEndian.PutUint32(h[84:], 128)
+	e := d.sector(2)
+	putGPTEntry(e[0:], "C12A7328-F81F-11D2-BA4B-00A0C93EC93B", 2048, 4095, "EFI System")
+	putGPTEntry(e[256:], "C91818F9-8025-47AF-89D2-F030D7000C2C", 4096, 99966, "9front")
+	copy(d.sector(4097), "part 9fat 0 1024\npart fs 1024 95871\n")
+	e = d.sector(3)
+	putGPTEntry(e[0:], "01234567-89AB-CDEF-0123-456789ABCDEF", 99967, 99979, "My Data")
+	putGPTEntry(e[128:], "01234567-89AB-CDEF-0123-456789ABCDEF", 99980, 99989, "")
+
+	parts, err := ReadPartitions(d, d.size, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Partition{
+		{"data", 0, 100000, 512},
+		{"esp", 2048, 4096, 512},
+		{"plan9", 4096, 99967, 512},
+		{"9fat", 4096, 5120, 512},
+		{"fs", 5120, 99967, 512},
+		{"My_Data", 99967, 99980, 512},
+		{"p6", 99980, 99990, 512},
+	}
+	if !cmp.Equal(want, parts) {
+		t.Errorf("ReadPartitions: %v", cmp.Diff(want, parts))
+	}
+}
+
+func TestReadPartitionsNoTable(t *testing.T) {
+	d := newSparseDisk(100 * 512)
+	parts, err := ReadPartitions(d, d.size, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Partition{
+		{"data", 0, 100, 512},
+	}
+	if !cmp.Equal(want, parts) {
+		t.Errorf("ReadPartitions: %v", cmp.Diff(want, parts))
+	}
+}
+
+func TestReadPartitionsInvalidSectorSize(t *testing.T) {
+	d := newSparseDisk(100 * 512)
+	if _, err := ReadPartitions(d, d.size, 256); err == nil {
+		t.Errorf("ReadPartitions: want an error")
+	}
+}
+
+func TestReadPartitionsInvalidGPT(t *testing.T) {
+	tests := []struct {
+		n, size uint32
+	}{
+		{0xffffffff, 0xffffffff},
+		{4, 0xffffffff},
+		{0xffffffff, 128},
+		{4, 64},
+	}
+	for _, tt := range tests {
+		d := newSparseDisk(1000 * 512)
+		putMBREntry(d.sector(0), 0, 0xee, 1, 999)
+		h := d.sector(1)
+		copy(h, "EFI PART")
+		binary.LittleEndian.PutUint64(h[72:], 2)
+		binary.LittleEndian.PutUint32(h[80:], tt.n)
+		binary.LittleEndian.PutUint32(h[84:], tt.size)
+		if _, err := ReadPartitions(d, d.size, 512); err == nil {
+			t.Errorf("ReadPartitions(n=%#x, size=%#x): want an error", tt.n, tt.size)
+		}
+	}
+}
+
+func TestReadPartitionsInvalidGPTEntry(t *testing.T) {
+	d := newSparseDisk(1000 * 512)
+	putMBREntry(d.sector(0), 0, 0xee, 1, 999)
+	h := d.sector(1)
+	copy(h, "EFI PART")
+	binary.LittleEndian.PutUint64(h[72:], 2)
+	binary.LittleEndian.PutUint32(h[80:], 4)
+	binary.LittleEndian.PutUint32(h[84:], 128)
+	putGPTEntry(d.sector(2), "C91818F9-8025-47AF-89D2-F030D7000C2C", 500, 100, "plan9")
+	if _, err := ReadPartitions(d, d.size, 512); err == nil {
+		t.Errorf("ReadPartitions: want an error")
+	}
+}
+
+func TestReadPartitionsInvalidSize(t *testing.T) {
+	d := newSparseDisk(100 * 512)
+	if _, err := ReadPartitions(d, -512, 512); err == nil {
+		t.Errorf("ReadPartitions: want an error")
+	}
+}
+
+// eofDisk returns io.EOF with the data if the read reaches the end of the disk.
+type eofDisk struct {
+	*sparseDisk
+}
+
+func (d eofDisk) ReadAt(p []byte, off int64) (int, error) {
+	n, err := d.sparseDisk.ReadAt(p, off)
+	if err == nil && off+int64(n) == d.size {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func TestReadPartitionsEOF(t *testing.T) {
+	d := newSparseDisk(1 * 512)
+	putMBREntry(d.sector(0), 0, 0x0c, 0, 1)
+	parts, err := ReadPartitions(eofDisk{d}, d.size, 512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Partition{
+		{"data", 0, 1, 512},
+		{"dos", 0, 1, 512},
+	}
+	if !cmp.Equal(want, parts) {
+		t.Errorf("ReadPartitions: %v", cmp.Diff(want, parts))
+	}
+}
@@ -0,0 +1,442 @@
+package stats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Process represents a process in /proc.
+type Process struct {
+	PID    uint32
+	Status ProcStatus
+	Args   []string // /proc/N/args
+	NoteID uint32   // /proc/N/noteid
+	PPID   uint32   // /proc/N/ppid; it is 0 if the kernel doesn't provide it
+}
+
+// ReadProcs reads processes from /proc.
+// Processes that exit while reading are not contained in the result.
+func ReadProcs(ctx context.Context, opts ...Option) ([]*Process, error) {
+	cfg := newConfig(opts...)
+	dir := filepath.Join(cfg.rootdir, "/proc")
+	procs, err := readProcs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range procs {
+		procdir := filepath.Join(dir, strconv.FormatUint(uint64(p.PID), 10))
+		if err := readProcInfo(procdir, p); err != nil {
+			return nil, err
+		}
+	}
+	return procs, nil
+}
+
+// readProcs reads status of processes in dir.
+// Processes that exit while reading are not contained in the result.
+func readProcs(dir string) ([]*Process, error) {
+	pids, err := readDirIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+	var a []*Process
+	for _, pid := range pids {
+		p := Process{PID: uint32(pid)}
+		file := filepath.Join(dir, strconv.Itoa(pid), "status")
+		if err := readProcStatus(file, &p.Status); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		a = append(a, &p)
+	}
+	return a, nil
+}
+
+// readProcInfo reads args, noteid and ppid of the process.
+// These files are optional because older kernels don't provide ppid,
+// args is readable only by the owner, and also the process may exit while reading.
+func readProcInfo(procdir string, p *Process) error {
+	s, err := readString(filepath.Join(procdir, "args"))
+	if err != nil && !isOptionalErr(err) {
+		return err
+	}
+	p.Args, err = tokenize(s)
+	if err != nil {
+		p.Args = strings.Fields(s)
+	}
+
+	m := []struct {
+		name string
+		p    *uint32
+	}{
+		{"noteid", &p.NoteID},
+		{"ppid", &p.PPID},
+	}
+	for _, v := range m {
+		s, err := readString(filepath.Join(procdir, v.name))
+		if isOptionalErr(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var up uint32parser
+		*v.p = up.Parse(s)
+		if err := up.err; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isOptionalErr reports whether err can be ignored on reading optional files.
+func isOptionalErr(err error) bool {
+	return os.IsNotExist(err) || os.IsPermission(err)
+}
+
+// ProcessNode is a node of ProcessTree.
+type ProcessNode struct {
+	*Process
+	Parent   *ProcessNode // nil if the parent is unknown
+	Children []*ProcessNode
+}
+
+// ProcessTree represents the parent-child relationship of processes.
+type ProcessTree struct {
+	Roots      []*ProcessNode // processes without known parent
+	Nodes      map[uint32]*ProcessNode
+	NoteGroups map[uint32][]*ProcessNode // processes grouped by NoteID
+}
+
+// NewProcessTree builds a tree of procs.
+// If the kernel doesn't provide ppid, all processes become roots.
+func NewProcessTree(procs []*Process) *ProcessTree {
+	t := ProcessTree{
+		Nodes:      make(map[uint32]*ProcessNode),
+		NoteGroups: make(map[uint32][]*ProcessNode),
+	}
+	a := make([]*ProcessNode, len(procs))
+	for i, p := range procs {
+		a[i] = &ProcessNode{Process: p}
+		t.Nodes[p.PID] = a[i]
+	}
+	for _, n := range a {
+		parent, ok := t.Nodes[n.PPID]
+		if n.PPID == 0 || !ok || parent == n {
+			t.Roots = append(t.Roots, n)
+		} else {
+			n.Parent = parent
+			parent.Children = append(parent.Children, n)
+		}
+		if n.NoteID != 0 {
+			t.NoteGroups[n.NoteID] = append(t.NoteGroups[n.NoteID], n)
+		}
+	}
+	return &t
+}
+
+// Segment represents a line of /proc/N/segment.
+type Segment struct {
+	Name     string // such as Text, Data, Bss, Stack, Shared and Physical
+	ReadOnly bool
+	Start    uint64
+	End      uint64
+	Ref      int // reference count
+}
+
+// Size returns the size of s in byte.
+func (s *Segment) Size() uint64 {
+	return s.End - s.Start
+}
+
+// ReadSegments reads memory segments of the process pid from /proc/N/segment.
+func ReadSegments(ctx context.Context, pid uint32, opts ...Option) ([]*Segment, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "/proc", strconv.FormatUint(uint64(pid), 10), "segment")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var a []*Segment
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s, err := parseSegment(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		a = append(a, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// parseSegment parses a line formed as "name flags start end ref".
+// The flags, such as R for read-only, may be blank.
+func parseSegment(s string) (*Segment, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 4 && len(fields) != 5 {
+		return nil, fmt.Errorf("invalid format: %s", s)
+	}
+	var (
+		p   intParser
+		seg Segment
+	)
+	n := len(fields)
+	seg.Name = fields[0]
+	if n == 5 {
+		seg.ReadOnly = strings.Contains(fields[1], "R")
+	}
+	seg.Start = p.ParseUint64(fields[n-3], 16)
+	seg.End = p.ParseUint64(fields[n-2], 16)
+	seg.Ref = p.ParseInt(fields[n-1], 10)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &seg, nil
+}
+
+// MemUsage represents memory usage summarized from segments.
+type MemUsage struct {
+	Size uint64 // total size of segments in byte
+
+	// Proportional is the size that each segment is divided by its reference count.
+	// Summing it over all processes counts shared segments, such as Text, only once.
+	Proportional uint64
+}
+
+// SumSegments summarizes memory usage of segs.
+func SumSegments(segs []*Segment) MemUsage {
+	var u MemUsage
+	for _, s := range segs {
+		u.Size += s.Size()
+		if s.Ref > 1 {
+			u.Proportional += s.Size() / uint64(s.Ref)
+		} else {
+			u.Proportional += s.Size()
+		}
+	}
+	return u
+}
+
+// Qid represents a qid of the file.
+type Qid struct {
+	Path uint64
+	Vers uint32
+	Type uint8
+}
+
+// FD represents a file descriptor in /proc/N/fd.
+type FD struct {
+	FD     int
+	Mode   string // r, w or rw
+	Type   rune   // device type, such as M for mount and I for ip(3)
+	Dev    int    // device instance
+	Qid    Qid
+	IOUnit int
+	Offset int64
+	Path   string
+}
+
+// FDTable represents /proc/N/fd.
+type FDTable struct {
+	PID uint32
+	Dir string // current working directory
+	FDs []*FD
+}
+
+// ReadFDs reads open files of the process pid from /proc/N/fd.
+func ReadFDs(ctx context.Context, pid uint32, opts ...Option) (*FDTable, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "/proc", strconv.FormatUint(uint64(pid), 10), "fd")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t := FDTable{PID: pid}
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		t.Dir = scanner.Text()
+	}
+	for scanner.Scan() {
+		fd, err := parseFD(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		t.FDs = append(t.FDs, fd)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ReadAllFDs reads open files of each process in procs.
+// Processes that exit while reading are not contained in the result.
+func ReadAllFDs(ctx context.Context, procs []*Process, opts ...Option) ([]*FDTable, error) {
+	var a []*FDTable
+	for _, p := range procs {
+		t, err := ReadFDs(ctx, p.PID, opts...)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, t)
+	}
+	return a, nil
+}
+
+// parseFD parses a line formed as "fd mode type dev (path vers type) iounit offset name".
+func parseFD(s string) (*FD, error) {
+	fields, name := splitFields(s, 9)
+	if len(fields) != 9 {
+		return nil, fmt.Errorf("invalid format: %s", s)
+	}
+	if !strings.HasPrefix(fields[4], "(") || !strings.HasSuffix(fields[6], ")") {
+		return nil, fmt.Errorf("invalid qid: %s", s)
+	}
+	var (
+		p  intParser
+		fd FD
+	)
+	fd.FD = p.ParseInt(fields[0], 10)
+	fd.Mode = fields[1]
+	fd.Type, _ = utf8.DecodeRuneInString(fields[2])
+	fd.Dev = p.ParseInt(fields[3], 10)
+	fd.Qid.Path = p.ParseUint64(fields[4][1:], 16)
+	fd.Qid.Vers = uint32(p.ParseUint64(fields[5], 10))
+	fd.Qid.Type = uint8(p.ParseUint64(strings.TrimSuffix(fields[6], ")"), 16))
+	fd.IOUnit = p.ParseInt(fields[7], 10)
+	fd.Offset = p.ParseInt64(fields[8], 10)
+	fd.Path = name
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &fd, nil
+}
+
+// splitFields splits s into first n fields separated by spaces and the rest.
+// The rest may contain spaces.
+func splitFields(s string, n int) ([]string, string) {
+	var a []string
+	for len(a) < n {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			break
+		}
+		i := strings.IndexAny(s, " \t")
+		if i < 0 {
+			a = append(a, s)
+			s = ""
+			break
+		}
+		a = append(a, s[:i])
+		s = s[i:]
+	}
+	return a, strings.TrimLeft(s, " \t")
+}
+
+// ProcUsage represents CPU utilization of a process between two samples.
+type ProcUsage struct {
+	Process *Process
+	Elapsed time.Duration // wall time between two samples
+
+	// percentages of Elapsed
+	User  float64
+	Sys   float64
+	Total float64
+
+	// percentages of total CPU capacity; that is Elapsed * NumCPU
+	UserCapacity  float64
+	SysCapacity   float64
+	TotalCapacity float64
+}
+
+// ProcSampler calculates CPU utilization of processes from successive samples.
+type ProcSampler struct {
+	NumCPU int // number of processors
+
+	prev map[uint32]*Process
+}
+
+// NewProcSampler returns a ProcSampler for the host.
+// NumCPU of it is set to the number of /dev/sysstat rows.
+func NewProcSampler(ctx context.Context, opts ...Option) (*ProcSampler, error) {
+	a, err := ReadSysStats(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcSampler{NumCPU: len(a)}, nil
+}
+
+// Sample records procs and returns the utilization of each process
+// since the previous sample in descending order of Total.
+// Processes that first appear in procs are not contained in the result.
+// If PID is reused by another process, it is treated as a new process.
+func (s *ProcSampler) Sample(procs []*Process) []*ProcUsage {
+	var a []*ProcUsage
+	m := make(map[uint32]*Process, len(procs))
+	for _, p := range procs {
+		m[p.PID] = p
+		prev, ok := s.prev[p.PID]
+		if !ok || isPIDReused(prev, p) {
+			continue
+		}
+		elapsed := p.Status.Times.Real - prev.Status.Times.Real
+		if elapsed <= 0 {
+			continue
+		}
+		u := ProcUsage{
+			Process: p,
+			Elapsed: elapsed,
+		}
+		user := p.Status.Times.User - prev.Status.Times.User
+		sys := p.Status.Times.Sys - prev.Status.Times.Sys
+		u.User = percent(user, elapsed)
+		u.Sys = percent(sys, elapsed)
+		u.Total = percent(user+sys, elapsed)
+		if s.NumCPU > 0 {
+			n := float64(s.NumCPU)
+			u.UserCapacity = u.User / n
+			u.SysCapacity = u.Sys / n
+			u.TotalCapacity = u.Total / n
+		}
+		a = append(a, &u)
+	}
+	s.prev = m
+	sort.SliceStable(a, func(i, j int) bool {
+		return a[i].Total > a[j].Total
+	})
+	return a
+}
+
+// isPIDReused reports whether p is another process than prev with the same PID.
+// Because CPU times of a process never decrease, a decrease of any of them means a new process.
+func isPIDReused(prev, p *Process) bool {
+	t0, t1 := prev.Status.Times, p.Status.Times
+	if prev.Status.Name != p.Status.Name {
+		return true
+	}
+	return t1.Real < t0.Real || t1.User < t0.User || t1.Sys < t0.Sys
+}
+
+func percent(d, total time.Duration) float64 {
+	return float64(d) / float64(total) * 100
+}
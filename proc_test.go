@@ -0,0 +1,279 @@
+package stats
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadProcs(t *testing.T) {
+	ctx := context.Background()
+	procs, err := ReadProcs(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Process{
+		&Process{
+			PID: 1,
+			Status: ProcStatus{
+				Name:  "init",
+				User:  "bootes",
+				State: "Await",
+				Times: CPUTime{
+					User:      10 * time.Millisecond,
+					Sys:       20 * time.Millisecond,
+					Real:      1404307210 * time.Millisecond,
+					ChildUser: 110 * time.Millisecond,
+					ChildSys:  20 * time.Millisecond,
+				},
+				MemUsed:      116,
+				BasePriority: 10,
+				Priority:     10,
+			},
+			Args:   []string{"/bin/init"},
+			NoteID: 1,
+		},
+		&Process{
+			PID: 72,
+			Status: ProcStatus{
+				Name:  "httpd",
+				User:  "none",
+				State: "Open",
+				Times: CPUTime{
+					User: 2380 * time.Millisecond,
+					Sys:  29690 * time.Millisecond,
+					Real: 1404804330 * time.Millisecond,
+				},
+				MemUsed:      23616,
+				BasePriority: 10,
+				Priority:     10,
+			},
+			Args:   []string{"ip/httpd/httpd", "-w", "/usr/web", "tcp!*!80"},
+			NoteID: 72,
+			PPID:   1,
+		},
+		&Process{
+			PID: 54384,
+			Status: ProcStatus{
+				Name:  "rc",
+				User:  "lufia",
+				State: "Await",
+				Times: CPUTime{
+					Real:      589160 * time.Millisecond,
+					ChildUser: 8770 * time.Millisecond,
+					ChildSys:  3260 * time.Millisecond,
+				},
+				MemUsed:      248,
+				BasePriority: 10,
+				Priority:     10,
+			},
+			Args:   []string{"rc", "-i"},
+			NoteID: 54384,
+			PPID:   1,
+		},
+		&Process{
+			PID: 54412,
+			Status: ProcStatus{
+				Name:  "git-remote-https",
+				User:  "lufia",
+				State: "Semacquire",
+				Times: CPUTime{
+					User: 390 * time.Millisecond,
+					Sys:  310 * time.Millisecond,
+					Real: 370670 * time.Millisecond,
+				},
+				MemUsed:      98368,
+				BasePriority: 10,
+				Priority:     10,
+			},
+			Args:   []string{"git-remote-https", "origin", "https://github.com/lufia/plan9stats"},
+			NoteID: 54384,
+			PPID:   54384,
+		},
+	}
+	if !cmp.Equal(want, procs) {
+		t.Errorf("ReadProcs: %v", cmp.Diff(want, procs))
+	}
+}
+
+func TestReadSegments(t *testing.T) {
+	ctx := context.Background()
+	segs, err := ReadSegments(ctx, 72, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Segment{
+		{Name: "Stack", Start: 0xdefff000, End: 0xdffff000, Ref: 1},
+		{Name: "Text", ReadOnly: true, Start: 0x1000, End: 0x4a000, Ref: 3},
+		{Name: "Data", Start: 0x4a000, End: 0x4f000, Ref: 1},
+		{Name: "Bss", Start: 0x4f000, End: 0x66d000, Ref: 1},
+	}
+	if !cmp.Equal(want, segs) {
+		t.Errorf("ReadSegments: %v", cmp.Diff(want, segs))
+	}
+}
+
+func TestSumSegments(t *testing.T) {
+	segs := []*Segment{
+		{Name: "Stack", Start: 0x1000, End: 0x2000, Ref: 1},
+		{Name: "Text", ReadOnly: true, Start: 0x2000, End: 0x5000, Ref: 3},
+	}
+	want := MemUsage{
+		Size:         0x4000,
+		Proportional: 0x1000 + 0x1000,
+	}
+	u := SumSegments(segs)
+	if !cmp.Equal(want, u) {
+		t.Errorf("SumSegments: %v", cmp.Diff(want, u))
+	}
+}
+
+func TestReadFDs(t *testing.T) {
+	ctx := context.Background()
+	fds, err := ReadFDs(ctx, 72, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &FDTable{
+		PID: 72,
+		Dir: "/usr/web",
+		FDs: []*FD{
+			{0, "r", 'M', 1, Qid{0xb4e2, 0, 0}, 8192, 0, "/dev/null"},
+			{1, "w", 'c', 0, Qid{0x2, 0, 0}, 0, 521, "/dev/cons"},
+			{2, "w", 'M', 8, Qid{0x18c, 3, 0}, 8192, 1024, "/sys/log/http log"},
+			{3, "rw", 'I', 0, Qid{0x5, 0, 0}, 0, 0, "/net/tcp/0/data"},
+			{4, "rw", '|', 0, Qid{0xb43, 0, 0}, 65536, 0, "#|/data1"},
+		},
+	}
+	if !cmp.Equal(want, fds) {
+		t.Errorf("ReadFDs: %v", cmp.Diff(want, fds))
+	}
+}
+
+func TestReadAllFDs(t *testing.T) {
+	ctx := context.Background()
+	procs := []*Process{{PID: 1}, {PID: 72}}
+	a, err := ReadAllFDs(ctx, procs, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 1 || a[0].PID != 72 {
+		t.Errorf("ReadAllFDs: got %v; want only the process 72", a)
+	}
+}
+
+func TestNewProcessTree(t *testing.T) {
+	procs := []*Process{
+		{PID: 1, NoteID: 1},
+		{PID: 72, NoteID: 72, PPID: 1},
+		{PID: 54384, NoteID: 54384, PPID: 1},
+		{PID: 54412, NoteID: 54384, PPID: 54384},
+		{PID: 60000, NoteID: 60000, PPID: 59999}, // the parent already exited
+	}
+	tree := NewProcessTree(procs)
+	nodes := tree.Nodes
+	if n := len(nodes); n != len(procs) {
+		t.Fatalf("len(Nodes) = %d; want %d", n, len(procs))
+	}
+	if a := tree.Roots; len(a) != 2 || a[0] != nodes[1] || a[1] != nodes[60000] {
+		t.Errorf("Roots = %v; want [1 60000]", a)
+	}
+	if a := nodes[1].Children; len(a) != 2 || a[0] != nodes[72] || a[1] != nodes[54384] {
+		t.Errorf("Children of 1 = %v; want [72 54384]", a)
+	}
+	if p := nodes[54412].Parent; p != nodes[54384] {
+		t.Errorf("Parent of 54412 = %v; want 54384", p)
+	}
+	if a := tree.NoteGroups[54384]; len(a) != 2 || a[0] != nodes[54384] || a[1] != nodes[54412] {
+		t.Errorf("NoteGroups[54384] = %v; want [54384 54412]", a)
+	}
+}
+
+func TestNewProcessTreeWithoutPPID(t *testing.T) {
+	procs := []*Process{
+		{PID: 1, NoteID: 1},
+		{PID: 72, NoteID: 1},
+	}
+	tree := NewProcessTree(procs)
+	if n := len(tree.Roots); n != 2 {
+		t.Errorf("len(Roots) = %d; want 2", n)
+	}
+	if n := len(tree.NoteGroups[1]); n != 2 {
+		t.Errorf("len(NoteGroups[1]) = %d; want 2", n)
+	}
+}
+
+func TestProcSampler(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewProcSampler(ctx, WithRootDir("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NumCPU != 2 {
+		t.Errorf("NumCPU = %d; want 2", s.NumCPU)
+	}
+	proc := func(pid uint32, name string, user, sys, real time.Duration) *Process {
+		return &Process{
+			PID: pid,
+			Status: ProcStatus{
+				Name:  name,
+				Times: CPUTime{User: user, Sys: sys, Real: real},
+			},
+		}
+	}
+	if a := s.Sample([]*Process{
+		proc(1, "init", 0, 0, 10*time.Second),
+		proc(72, "httpd", time.Second, time.Second, 10*time.Second),
+		proc(100, "rc", 0, 0, 10*time.Second),
+		proc(300, "rc", 3*time.Second, time.Second, 10*time.Second),
+	}); len(a) != 0 {
+		t.Errorf("Sample: got %v at first; want empty", a)
+	}
+	p72 := proc(72, "httpd", 1500*time.Millisecond, 2*time.Second, 12*time.Second)
+	a := s.Sample([]*Process{
+		proc(1, "init", 0, 0, 12*time.Second),
+		p72,
+		proc(100, "sam", 0, 0, time.Second), // PID is reused
+		proc(200, "cat", 0, 0, time.Second),
+		proc(300, "rc", time.Second, 0, 15*time.Second), // PID is reused, but Real doesn't go down
+	})
+	want := []*ProcUsage{
+		{
+			Process:       p72,
+			Elapsed:       2 * time.Second,
+			User:          25,
+			Sys:           50,
+			Total:         75,
+			UserCapacity:  12.5,
+			SysCapacity:   25,
+			TotalCapacity: 37.5,
+		},
+		{
+			Process: proc(1, "init", 0, 0, 12*time.Second),
+			Elapsed: 2 * time.Second,
+		},
+	}
+	if !cmp.Equal(want, a) {
+		t.Errorf("Sample: %v", cmp.Diff(want, a))
+	}
+}
+
+func TestIsOptionalErr(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&fs.PathError{Op: "open", Path: "/proc/1/args", Err: fs.ErrPermission}, true},
+		{&fs.PathError{Op: "open", Path: "/proc/1/ppid", Err: fs.ErrNotExist}, true},
+		{&fs.PathError{Op: "read", Path: "/proc/1/args", Err: io.ErrUnexpectedEOF}, false},
+	}
+	for _, tt := range tests {
+		if v := isOptionalErr(tt.err); v != tt.want {
+			t.Errorf("isOptionalErr(%v) = %t; want %t", tt.err, v, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Route represents an entry of /net/iproute.
+type Route struct {
+	Dest    net.IP
+	Mask    net.IPMask
+	Gateway net.IP
+	Flags   string // such as 4, 4i, 4u and 6b
+	Tag     string
+	Ifc     int // number of the interface; it is -1 if no interface is associated
+}
+
+// IsDefault reports whether r is a default route.
+func (r *Route) IsDefault() bool {
+	ones, _ := r.Mask.Size()
+	return ones == 0
+}
+
+// RouteTable represents /net/iproute.
+type RouteTable []*Route
+
+// ReadRoutes reads the routing table from iproute.
+func ReadRoutes(ctx context.Context, opts ...Option) (RouteTable, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "iproute")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var t RouteTable
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		r, err := parseRoute(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		t = append(t, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseRoute parses a line formed as "dest mask gateway flags tag ifc".
+// Recent kernels may append source address and mask; they are ignored.
+func parseRoute(s string) (*Route, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("invalid format: %s", s)
+	}
+	dest, err := parseIP(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	mask, err := parseIPMask(fields[1], dest)
+	if err != nil {
+		return nil, err
+	}
+	gw, err := parseIP(fields[2])
+	if err != nil {
+		return nil, err
+	}
+	r := Route{
+		Dest:    dest,
+		Mask:    mask,
+		Gateway: gw,
+		Flags:   fields[3],
+		Tag:     fields[4],
+		Ifc:     -1,
+	}
+	if fields[5] != "-" {
+		r.Ifc, err = strconv.Atoi(fields[5])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// Lookup returns the route that has the longest prefix matched to ip.
+// If there is no route to ip, Lookup returns nil.
+func (t RouteTable) Lookup(ip net.IP) *Route {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	var (
+		found   *Route
+		longest = -1
+	)
+	for _, r := range t {
+		if len(r.Dest) != len(ip) || len(r.Mask) != len(ip) {
+			continue
+		}
+		if !ip.Mask(r.Mask).Equal(r.Dest.Mask(r.Mask)) {
+			continue
+		}
+		if ones, _ := r.Mask.Size(); ones > longest {
+			found = r
+			longest = ones
+		}
+	}
+	return found
+}
+
+// Default returns the default route for IPv4 if v6 is false, otherwise for IPv6.
+// If there is no default route, Default returns nil.
+func (t RouteTable) Default(v6 bool) *Route {
+	n := net.IPv4len
+	if v6 {
+		n = net.IPv6len
+	}
+	for _, r := range t {
+		if len(r.Dest) == n && r.IsDefault() {
+			return r
+		}
+	}
+	return nil
+}
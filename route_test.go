@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadRoutes(t *testing.T) {
+	ctx := context.Background()
+	routes, err := ReadRoutes(ctx, WithRootDir("testdata/net"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v4 := func(a, b, c, d byte) net.IP {
+		return net.IPv4(a, b, c, d).To4()
+	}
+	want := RouteTable{
+		{v4(0, 0, 0, 0), net.CIDRMask(0, 32), v4(192, 168, 1, 1), "4", "none", -1},
+		{v4(127, 0, 0, 0), net.CIDRMask(8, 32), v4(127, 0, 0, 0), "4i", "ifc", 1},
+		{v4(127, 0, 0, 1), net.CIDRMask(32, 32), v4(127, 0, 0, 1), "4u", "ifc", 1},
+		{v4(192, 168, 1, 0), net.CIDRMask(24, 32), v4(192, 168, 1, 0), "4i", "ifc", 0},
+		{v4(192, 168, 1, 23), net.CIDRMask(32, 32), v4(192, 168, 1, 23), "4u", "ifc", 0},
+		{v4(192, 168, 1, 255), net.CIDRMask(32, 32), v4(192, 168, 1, 255), "4b", "ifc", 0},
+		{v4(10, 0, 0, 0), net.CIDRMask(8, 32), v4(192, 168, 1, 254), "4", "none", -1},
+		{net.ParseIP("fe80::"), net.CIDRMask(64, 128), net.ParseIP("fe80::"), "6i", "ifc", 0},
+	}
+	if !cmp.Equal(want, routes) {
+		t.Errorf("ReadRoutes: %v", cmp.Diff(want, routes))
+	}
+
+	tests := []struct {
+		ip   net.IP
+		want *Route
+	}{
+		{net.IPv4(192, 168, 1, 10), routes[3]},
+		{net.IPv4(192, 168, 1, 23), routes[4]},
+		{net.IPv4(10, 1, 2, 3), routes[6]},
+		{net.IPv4(8, 8, 8, 8), routes[0]},
+		{net.ParseIP("fe80::1"), routes[7]},
+		{net.ParseIP("2001:db8::1"), nil},
+	}
+	for _, tt := range tests {
+		if r := routes.Lookup(tt.ip); r != tt.want {
+			t.Errorf("Lookup(%v) = %v; want %v", tt.ip, r, tt.want)
+		}
+	}
+	if r := routes.Default(false); r != routes[0] {
+		t.Errorf("Default(false) = %v; want %v", r, routes[0])
+	}
+	if r := routes.Default(true); r != nil {
+		t.Errorf("Default(true) = %v; want nil", r)
+	}
+}
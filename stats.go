@@ -3,6 +3,7 @@ package stats
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -78,6 +79,123 @@ func ReadInterfaceStats(ctx context.Context, opts ...Option) (*InterfaceStats, e
 	return &stats, nil
 }
 
+// IfStats represents /net/etherN/ifstats.
+// Because its format depends on the driver, IfStats holds well-known counters
+// in typed fields and keeps every other key in Raw.
+type IfStats struct {
+	GoodPacketsReceived  int64 // Good Packets Received
+	GoodPacketsSent      int64 // Good Packets Transmitted
+	OctetsReceived       int64 // Total Octets Received
+	OctetsSent           int64 // Total Octets Transmitted
+	TotalPacketsReceived int64 // Total Packets Received
+	TotalPacketsSent     int64 // Total Packets Transmitted
+
+	LinkIntr    int64 // link interrupts
+	ReceiveIntr int64 // receive interrupts
+	SendIntr    int64 // transmit interrupts
+
+	Queues map[string]*QueueStats // such as "rcv bufs unprocessed"
+	Raw    map[string]string      // other keys
+}
+
+// QueueStats represents a line formed as "highwater n/max curr n hitmax n" in ifstats.
+type QueueStats struct {
+	Highwater int
+	Max       int
+	Curr      int
+	HitMax    int
+}
+
+// ReadIfStats reads driver-specific statistics from ifstats file in the interface directory such as /net/ether0.
+//
+// Multi-line values, such as eeprom or phy dumps, are not stored.
+func ReadIfStats(ctx context.Context, opts ...Option) (*IfStats, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "ifstats")
+	stats := IfStats{
+		Queues: make(map[string]*QueueStats),
+		Raw:    make(map[string]string),
+	}
+	err := readKeyValues(file, func(key, v string) error {
+		if strings.Contains(v, "\n") {
+			return nil
+		}
+		if strings.HasPrefix(v, "highwater ") {
+			q, err := parseQueueStats(v)
+			if err != nil {
+				return err
+			}
+			stats.Queues[key] = q
+			return nil
+		}
+		// Some drivers append the delta since last read after the total.
+		var total string
+		if fields := strings.Fields(v); len(fields) > 0 {
+			total = fields[0]
+		}
+		var p intParser
+		switch key {
+		case "Good Packets Received":
+			stats.GoodPacketsReceived = p.ParseInt64(total, 10)
+		case "Good Packets Transmitted":
+			stats.GoodPacketsSent = p.ParseInt64(total, 10)
+		case "Total Octets Received":
+			stats.OctetsReceived = p.ParseInt64(total, 10)
+		case "Total Octets Transmitted":
+			stats.OctetsSent = p.ParseInt64(total, 10)
+		case "Total Packets Received":
+			stats.TotalPacketsReceived = p.ParseInt64(total, 10)
+		case "Total Packets Transmitted":
+			stats.TotalPacketsSent = p.ParseInt64(total, 10)
+		case "lintr":
+			stats.LinkIntr = p.ParseInt64(total, 10)
+		case "rintr":
+			stats.ReceiveIntr = p.ParseInt64(total, 10)
+		case "tintr":
+			stats.SendIntr = p.ParseInt64(total, 10)
+		default:
+			stats.Raw[key] = v
+		}
+		return p.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+func parseQueueStats(s string) (*QueueStats, error) {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("invalid format: %s", s)
+	}
+	var (
+		p intParser
+		q QueueStats
+	)
+	for i := 0; i < len(fields); i += 2 {
+		v := fields[i+1]
+		switch fields[i] {
+		case "highwater":
+			n, m, ok := strings.Cut(v, "/")
+			if !ok {
+				return nil, fmt.Errorf("invalid highwater: %s", v)
+			}
+			q.Highwater = p.ParseInt(n, 10)
+			q.Max = p.ParseInt(m, 10)
+		case "curr":
+			q.Curr = p.ParseInt(v, 10)
+		case "hitmax":
+			q.HitMax = p.ParseInt(v, 10)
+		}
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+// TCPStats represents /net/tcp/stats.
 type TCPStats struct {
 	MaxConn            int
 	MaxSegment         int
@@ -85,4 +203,371 @@ type TCPStats struct {
 	PassiveOpens       int
 	EstablishedResets  int
 	CurrentEstablished int
+
+	InSegs          int64 // segments received
+	OutSegs         int64 // segments sent
+	RetransSegs     int64 // segments retransmitted
+	RetransSegsSent int64
+	RetransTimeouts int64 // retransmission timeouts
+	InErrs          int64 // segments received in error
+	OutRsts         int64 // resets sent
+	CsumErrs        int64 // checksum errors
+	HlenErrs        int64 // header length errors
+	LenErrs         int64 // length errors
+	Resequenced     int64 // segments resequenced
+	OutOfOrder      int64 // segments received out of order
+	ReseqBytelim    int64 // resequence queue overflows by bytes
+	ReseqPktlim     int64 // resequence queue overflows by packets
+	Delayack        int64 // delayed acks
+	Wopenack        int64 // window open acks
+	Recovery        int64 // fast recoveries entered
+	RecoveryDone    int64 // fast recoveries completed
+	RecoveryRTO     int64 // fast recoveries aborted by timeouts
+	RecoveryNoSeq   int64
+	RecoveryCwind   int64
+	RecoveryPA      int64 // partial acks during recoveries
+}
+
+// ReadTCPStats reads TCP statistics from stats file in the TCP directory such as /net/tcp.
+func ReadTCPStats(ctx context.Context, opts ...Option) (*TCPStats, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "stats")
+	var stats TCPStats
+	err := readKeyValues(file, func(key, v string) error {
+		var p intParser
+		switch key {
+		case "MaxConn":
+			stats.MaxConn = p.ParseInt(v, 10)
+		case "MaxSegment":
+			stats.MaxSegment = p.ParseInt(v, 10)
+		case "ActiveOpens":
+			stats.ActiveOpens = p.ParseInt(v, 10)
+		case "PassiveOpens":
+			stats.PassiveOpens = p.ParseInt(v, 10)
+		case "EstabResets":
+			stats.EstablishedResets = p.ParseInt(v, 10)
+		case "CurrEstab":
+			stats.CurrentEstablished = p.ParseInt(v, 10)
+		case "InSegs":
+			stats.InSegs = p.ParseInt64(v, 10)
+		case "OutSegs":
+			stats.OutSegs = p.ParseInt64(v, 10)
+		case "RetransSegs":
+			stats.RetransSegs = p.ParseInt64(v, 10)
+		case "RetransSegsSent":
+			stats.RetransSegsSent = p.ParseInt64(v, 10)
+		case "RetransTimeouts":
+			stats.RetransTimeouts = p.ParseInt64(v, 10)
+		case "InErrs":
+			stats.InErrs = p.ParseInt64(v, 10)
+		case "OutRsts":
+			stats.OutRsts = p.ParseInt64(v, 10)
+		case "CsumErrs":
+			stats.CsumErrs = p.ParseInt64(v, 10)
+		case "HlenErrs":
+			stats.HlenErrs = p.ParseInt64(v, 10)
+		case "LenErrs":
+			stats.LenErrs = p.ParseInt64(v, 10)
+		case "Resequenced":
+			stats.Resequenced = p.ParseInt64(v, 10)
+		case "OutOfOrder":
+			stats.OutOfOrder = p.ParseInt64(v, 10)
+		case "ReseqBytelim":
+			stats.ReseqBytelim = p.ParseInt64(v, 10)
+		case "ReseqPktlim":
+			stats.ReseqPktlim = p.ParseInt64(v, 10)
+		case "Delayack":
+			stats.Delayack = p.ParseInt64(v, 10)
+		case "Wopenack":
+			stats.Wopenack = p.ParseInt64(v, 10)
+		case "Recovery":
+			stats.Recovery = p.ParseInt64(v, 10)
+		case "RecoveryDone":
+			stats.RecoveryDone = p.ParseInt64(v, 10)
+		case "RecoveryRTO":
+			stats.RecoveryRTO = p.ParseInt64(v, 10)
+		case "RecoveryNoSeq":
+			stats.RecoveryNoSeq = p.ParseInt64(v, 10)
+		case "RecoveryCwind":
+			stats.RecoveryCwind = p.ParseInt64(v, 10)
+		case "RecoveryPA":
+			stats.RecoveryPA = p.ParseInt64(v, 10)
+		}
+		return p.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// UDPStats represents /net/udp/stats.
+type UDPStats struct {
+	InDatagrams  int64 // datagrams delivered
+	NoPorts      int64 // datagrams received for no listeners
+	InErrors     int64 // datagrams received in error
+	OutDatagrams int64 // datagrams sent
+	CsumErrs     int64 // checksum errors, if reported
+	LenErrs      int64 // length errors, if reported
+}
+
+// ReadUDPStats reads UDP statistics from stats file in the UDP directory such as /net/udp.
+func ReadUDPStats(ctx context.Context, opts ...Option) (*UDPStats, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "stats")
+	var stats UDPStats
+	err := readKeyValues(file, func(key, v string) error {
+		var p intParser
+		switch key {
+		case "InDatagrams":
+			stats.InDatagrams = p.ParseInt64(v, 10)
+		case "NoPorts":
+			stats.NoPorts = p.ParseInt64(v, 10)
+		case "InErrors":
+			stats.InErrors = p.ParseInt64(v, 10)
+		case "OutDatagrams":
+			stats.OutDatagrams = p.ParseInt64(v, 10)
+		case "CsumErrs":
+			stats.CsumErrs = p.ParseInt64(v, 10)
+		case "LenErrs":
+			stats.LenErrs = p.ParseInt64(v, 10)
+		}
+		return p.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// IPProtoStats represents /net/ipifc/stats.
+type IPProtoStats struct {
+	Forwarding      int64 // 1 if forwarding is enabled
+	DefaultTTL      int64
+	InReceives      int64
+	InHdrErrors     int64 // datagrams discarded due to header errors
+	InAddrErrors    int64 // datagrams discarded due to invalid destination
+	ForwDatagrams   int64
+	InUnknownProtos int64
+	InDiscards      int64
+	InDelivers      int64
+	OutRequests     int64
+	OutDiscards     int64
+	OutNoRoutes     int64 // datagrams discarded because no route could be found
+	ReasmTimeout    int64
+	ReasmReqds      int64
+	ReasmOKs        int64
+	ReasmFails      int64 // failures of reassembly
+	FragOKs         int64
+	FragFails       int64
+	FragCreates     int64 // fragments created
+}
+
+// ReadIPProtoStats reads IP statistics from stats file in the IP interface directory such as /net/ipifc.
+func ReadIPProtoStats(ctx context.Context, opts ...Option) (*IPProtoStats, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "stats")
+	var stats IPProtoStats
+	err := readKeyValues(file, func(key, v string) error {
+		var p intParser
+		switch key {
+		case "Forwarding":
+			stats.Forwarding = p.ParseInt64(v, 10)
+		case "DefaultTTL":
+			stats.DefaultTTL = p.ParseInt64(v, 10)
+		case "InReceives":
+			stats.InReceives = p.ParseInt64(v, 10)
+		case "InHdrErrors":
+			stats.InHdrErrors = p.ParseInt64(v, 10)
+		case "InAddrErrors":
+			stats.InAddrErrors = p.ParseInt64(v, 10)
+		case "ForwDatagrams":
+			stats.ForwDatagrams = p.ParseInt64(v, 10)
+		case "InUnknownProtos":
+			stats.InUnknownProtos = p.ParseInt64(v, 10)
+		case "InDiscards":
+			stats.InDiscards = p.ParseInt64(v, 10)
+		case "InDelivers":
+			stats.InDelivers = p.ParseInt64(v, 10)
+		case "OutRequests":
+			stats.OutRequests = p.ParseInt64(v, 10)
+		case "OutDiscards":
+			stats.OutDiscards = p.ParseInt64(v, 10)
+		case "OutNoRoutes":
+			stats.OutNoRoutes = p.ParseInt64(v, 10)
+		case "ReasmTimeout":
+			stats.ReasmTimeout = p.ParseInt64(v, 10)
+		case "ReasmReqds":
+			stats.ReasmReqds = p.ParseInt64(v, 10)
+		case "ReasmOKs":
+			stats.ReasmOKs = p.ParseInt64(v, 10)
+		case "ReasmFails":
+			stats.ReasmFails = p.ParseInt64(v, 10)
+		case "FragOKs":
+			stats.FragOKs = p.ParseInt64(v, 10)
+		case "FragFails":
+			stats.FragFails = p.ParseInt64(v, 10)
+		case "FragCreates":
+			stats.FragCreates = p.ParseInt64(v, 10)
+		}
+		return p.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// ICMPStats represents /net/icmp/stats.
+type ICMPStats struct {
+	InMsgs   int64
+	InErrors int64
+	OutMsgs  int64
+	CsumErrs int64 // checksum errors
+	LenErrs  int64 // length errors
+	HlenErrs int64 // header length errors
+
+	EchoReply       ICMPTypeStats
+	Unreachable     ICMPTypeStats
+	SrcQuench       ICMPTypeStats
+	Redirect        ICMPTypeStats
+	EchoRequest     ICMPTypeStats
+	TimeExceeded    ICMPTypeStats
+	InParmProblem   ICMPTypeStats
+	Timestamp       ICMPTypeStats
+	TimestampReply  ICMPTypeStats
+	InfoRequest     ICMPTypeStats
+	InfoReply       ICMPTypeStats
+	AddrMaskRequest ICMPTypeStats
+	AddrMaskReply   ICMPTypeStats
+
+	Raw map[string]string // other keys
+}
+
+// ICMPTypeStats represents the numbers of ICMP messages of a type.
+type ICMPTypeStats struct {
+	In  int64
+	Out int64
+}
+
+// ReadICMPStats reads ICMP statistics from stats file in the ICMP directory such as /net/icmp.
+func ReadICMPStats(ctx context.Context, opts ...Option) (*ICMPStats, error) {
+	cfg := newConfig(opts...)
+	file := filepath.Join(cfg.rootdir, "stats")
+	stats := ICMPStats{
+		Raw: make(map[string]string),
+	}
+	err := readKeyValues(file, func(key, v string) error {
+		var (
+			p   intParser
+			err error
+		)
+		switch key {
+		case "InMsgs":
+			stats.InMsgs = p.ParseInt64(v, 10)
+		case "InErrors":
+			stats.InErrors = p.ParseInt64(v, 10)
+		case "OutMsgs":
+			stats.OutMsgs = p.ParseInt64(v, 10)
+		case "CsumErrs":
+			stats.CsumErrs = p.ParseInt64(v, 10)
+		case "LenErrs":
+			stats.LenErrs = p.ParseInt64(v, 10)
+		case "HlenErrs":
+			stats.HlenErrs = p.ParseInt64(v, 10)
+		case "EchoReply":
+			stats.EchoReply, err = parseICMPTypeStats(v)
+		case "Unreachable":
+			stats.Unreachable, err = parseICMPTypeStats(v)
+		case "SrcQuench":
+			stats.SrcQuench, err = parseICMPTypeStats(v)
+		case "Redirect":
+			stats.Redirect, err = parseICMPTypeStats(v)
+		case "EchoRequest":
+			stats.EchoRequest, err = parseICMPTypeStats(v)
+		case "TimeExceeded":
+			stats.TimeExceeded, err = parseICMPTypeStats(v)
+		case "InParmProblem":
+			stats.InParmProblem, err = parseICMPTypeStats(v)
+		case "Timestamp":
+			stats.Timestamp, err = parseICMPTypeStats(v)
+		case "TimestampReply":
+			stats.TimestampReply, err = parseICMPTypeStats(v)
+		case "InfoRequest":
+			stats.InfoRequest, err = parseICMPTypeStats(v)
+		case "InfoReply":
+			stats.InfoReply, err = parseICMPTypeStats(v)
+		case "AddrMaskRequest":
+			stats.AddrMaskRequest, err = parseICMPTypeStats(v)
+		case "AddrMaskReply":
+			stats.AddrMaskReply, err = parseICMPTypeStats(v)
+		default:
+			stats.Raw[key] = v
+		}
+		if err != nil {
+			return err
+		}
+		return p.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// parseICMPTypeStats parses s formed as "in out".
+func parseICMPTypeStats(s string) (ICMPTypeStats, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return ICMPTypeStats{}, fmt.Errorf("invalid format: %s", s)
+	}
+	var p intParser
+	t := ICMPTypeStats{
+		In:  p.ParseInt64(fields[0], 10),
+		Out: p.ParseInt64(fields[1], 10),
+	}
+	return t, p.Err()
+}
+
+// readKeyValues reads file that consists of "key: value" lines, and calls fn with each key and its value.
+// Indented lines continue the value of the previous line, and they are joined with newlines.
+// Errors returned by fn are prefixed with the file name and the key.
+func readKeyValues(file string, fn func(key, value string) error) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var key, value string
+	flush := func() error {
+		if key == "" {
+			return nil
+		}
+		if err := fn(key, value); err != nil {
+			return fmt.Errorf("%s: %s: %w", file, key, err)
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		s := scanner.Text()
+		if key != "" && (strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t")) {
+			value += "\n" + strings.TrimSpace(s)
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		k, v, ok := strings.Cut(s, ":")
+		if !ok {
+			key = ""
+			continue
+		}
+		key, value = k, strings.TrimSpace(v)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
 }
@@ -2,6 +2,7 @@ package stats
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -68,3 +69,163 @@ func TestReadInterfaceStats(t *testing.T) {
 		t.Errorf("ReadInterfaceStats: %v", cmp.Diff(want, stats))
 	}
 }
+
+func TestReadTCPStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadTCPStats(ctx, WithRootDir("testdata/net/tcp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TCPStats{
+		MaxConn:            4096,
+		MaxSegment:         1460,
+		ActiveOpens:        558,
+		PassiveOpens:       8,
+		EstablishedResets:  44,
+		CurrentEstablished: 2,
+		InSegs:             1032190,
+		OutSegs:            1074542,
+		RetransSegs:        1653,
+		RetransSegsSent:    1653,
+		RetransTimeouts:    1468,
+		InErrs:             8,
+		OutRsts:            12601,
+		CsumErrs:           8,
+		HlenErrs:           0,
+		LenErrs:            0,
+		Resequenced:        1776,
+		OutOfOrder:         0,
+		ReseqBytelim:       0,
+		ReseqPktlim:        0,
+		Delayack:           28879,
+		Wopenack:           12207,
+		Recovery:           112,
+		RecoveryDone:       103,
+		RecoveryRTO:        8,
+		RecoveryNoSeq:      11,
+		RecoveryCwind:      2271,
+		RecoveryPA:         73,
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadTCPStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestReadUDPStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadUDPStats(ctx, WithRootDir("testdata/net/udp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &UDPStats{
+		InDatagrams:  36147,
+		NoPorts:      21,
+		InErrors:     3,
+		OutDatagrams: 36190,
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadUDPStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestReadIfStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadIfStats(ctx, WithRootDir("testdata/net/ether0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &IfStats{
+		GoodPacketsReceived:  11644636,
+		GoodPacketsSent:      269214,
+		OctetsReceived:       766515009,
+		OctetsSent:           136030888,
+		TotalPacketsReceived: 11644636,
+		TotalPacketsSent:     269214,
+		LinkIntr:             0,
+		ReceiveIntr:          8726641,
+		SendIntr:             0,
+		Queues: map[string]*QueueStats{
+			"rcv bufs unprocessed": &QueueStats{
+				Highwater: 106,
+				Max:       1024,
+				Curr:      7,
+				HitMax:    0,
+			},
+			"rcv descrs processed at once": &QueueStats{
+				Highwater: 127,
+				Max:       127,
+				Curr:      1,
+				HitMax:    1,
+			},
+			"xmit descr queue len": &QueueStats{
+				Highwater: 2,
+				Max:       31,
+				Curr:      1,
+				HitMax:    0,
+			},
+		},
+		Raw: map[string]string{
+			"ixcs":    "0 0 0",
+			"rdtr":    "0",
+			"Ctrlext": "00000000",
+		},
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadIfStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestReadIPProtoStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadIPProtoStats(ctx, WithRootDir("testdata/net/ipifc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &IPProtoStats{
+		Forwarding:  0,
+		DefaultTTL:  255,
+		InReceives:  1037210,
+		InDelivers:  1037207,
+		OutRequests: 1206755,
+		ReasmReqds:  1,
+		ReasmOKs:    1,
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadIPProtoStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestReadICMPStats(t *testing.T) {
+	ctx := context.Background()
+	stats, err := ReadICMPStats(ctx, WithRootDir("testdata/net/icmp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ICMPStats{
+		InMsgs:       1510,
+		InErrors:     2,
+		OutMsgs:      1497,
+		CsumErrs:     1,
+		LenErrs:      0,
+		HlenErrs:     1,
+		EchoReply:    ICMPTypeStats{In: 12, Out: 1480},
+		Unreachable:  ICMPTypeStats{In: 1492, Out: 5},
+		EchoRequest:  ICMPTypeStats{In: 1480, Out: 12},
+		TimeExceeded: ICMPTypeStats{In: 4},
+		Raw:          map[string]string{},
+	}
+	if !cmp.Equal(want, stats) {
+		t.Errorf("ReadICMPStats: %v", cmp.Diff(want, stats))
+	}
+}
+
+func TestReadICMPStatsInvalid(t *testing.T) {
+	ctx := context.Background()
+	_, err := ReadICMPStats(ctx, WithRootDir("testdata/invalid/icmp"))
+	if err == nil {
+		t.Fatal("ReadICMPStats: want an error")
+	}
+	if s := err.Error(); !strings.Contains(s, "stats: EchoReply: invalid format") {
+		t.Errorf("ReadICMPStats: got %q; want the file name and the key", s)
+	}
+}
@@ -0,0 +1,142 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+)
+
+// TCPConn represents a TCP conversation in /net/tcp/N.
+type TCPConn struct {
+	ID         int
+	LocalAddr  net.IP
+	LocalPort  int
+	RemoteAddr net.IP
+	RemotePort int
+	State      string // such as Established, Finwait2 and Closed
+
+	Qin  int // bytes in the input queue
+	Qout int // bytes in the output queue
+
+	NumReseq int // segments in the resequencing queue
+	ReseqLen int // bytes in the resequencing queue
+
+	SRTT int // smoothed round trip time
+	MDev int // mean deviation of round trip time
+
+	SST        int64 // slow start threshold
+	CWin       int64 // congestion window
+	SendWindow int64 // send window
+	SendScale  int   // send window scale
+	RecvWindow int64 // receive window
+	RecvScale  int   // receive window scale
+	QScale     int   // scale of the input queue
+
+	Timer     TCPTimer // retransmission timer
+	ReRecv    int      // retransmitted segments received
+	KeepAlive TCPTimer // keep-alive timer
+}
+
+// TCPTimer represents a timer of the TCP conversation.
+type TCPTimer struct {
+	Start int // initial value in ticks
+	Count int // remaining ticks
+}
+
+// ReadTCPConns reads TCP conversations from the TCP directory such as /net/tcp.
+func ReadTCPConns(ctx context.Context, opts ...Option) ([]*TCPConn, error) {
+	cfg := newConfig(opts...)
+	ids, err := readDirIDs(cfg.rootdir)
+	if err != nil {
+		return nil, err
+	}
+	var a []*TCPConn
+	for _, id := range ids {
+		c, err := readTCPConn(cfg.rootdir, id)
+		if os.IsNotExist(err) {
+			continue // the conversation is removed while reading
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, c)
+	}
+	return a, nil
+}
+
+func readTCPConn(protodir string, id int) (*TCPConn, error) {
+	c := TCPConn{ID: id}
+	v, err := readConv(protodir, id, func(s string) error {
+		return parseTCPStatus(s, &c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.LocalAddr, c.LocalPort = v.LocalAddr, v.LocalPort
+	c.RemoteAddr, c.RemotePort = v.RemoteAddr, v.RemotePort
+	return &c, nil
+}
+
+func parseTCPStatus(s string, c *TCPConn) error {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 1 {
+		return errors.New("invalid format")
+	}
+	c.State = fields[0]
+	var p intParser
+	for i := 1; i < len(fields); i += 2 {
+		v := fields[i+1]
+		switch fields[i] {
+		case "qin":
+			c.Qin = p.ParseInt(v, 10)
+		case "qout":
+			c.Qout = p.ParseInt(v, 10)
+		case "rq":
+			n, l, ok := strings.Cut(v, ".")
+			if !ok {
+				return fmt.Errorf("invalid rq: %s", v)
+			}
+			c.NumReseq = p.ParseInt(n, 10)
+			c.ReseqLen = p.ParseInt(l, 10)
+		case "srtt":
+			c.SRTT = p.ParseInt(v, 10)
+		case "mdev":
+			c.MDev = p.ParseInt(v, 10)
+		case "sst":
+			c.SST = p.ParseInt64(v, 10)
+		case "cwin":
+			c.CWin = p.ParseInt64(v, 10)
+		case "swin":
+			w, scale := parseWindow(&p, v)
+			c.SendWindow, c.SendScale = w, scale
+		case "rwin":
+			w, scale := parseWindow(&p, v)
+			c.RecvWindow, c.RecvScale = w, scale
+		case "qscale":
+			c.QScale = p.ParseInt(v, 10)
+		case "timer.start":
+			c.Timer.Start = p.ParseInt(v, 10)
+		case "timer.count":
+			c.Timer.Count = p.ParseInt(v, 10)
+		case "rerecv":
+			c.ReRecv = p.ParseInt(v, 10)
+		case "katimer.start":
+			c.KeepAlive.Start = p.ParseInt(v, 10)
+		case "katimer.count":
+			c.KeepAlive.Count = p.ParseInt(v, 10)
+		}
+	}
+	return p.Err()
+}
+
+// parseWindow parses s formed as "window>>scale".
+func parseWindow(p *intParser, s string) (int64, int) {
+	w, scale, ok := strings.Cut(s, ">>")
+	if !ok {
+		return p.ParseInt64(s, 10), 0
+	}
+	return p.ParseInt64(w, 10), p.ParseInt(scale, 10)
+}
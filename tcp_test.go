@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadTCPConns(t *testing.T) {
+	ctx := context.Background()
+	conns, err := ReadTCPConns(ctx, WithRootDir("testdata/net/tcp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*TCPConn{
+		&TCPConn{
+			ID:         0,
+			LocalAddr:  net.IPv4(192, 168, 1, 23).To4(),
+			LocalPort:  17011,
+			RemoteAddr: net.IPv4(192, 168, 1, 5).To4(),
+			RemotePort: 564,
+			State:      "Finwait2",
+			NumReseq:   1,
+			ReseqLen:   0,
+			SRTT:       260,
+			MDev:       110,
+			SST:        1048560,
+			CWin:       8716,
+			SendWindow: 40960,
+			SendScale:  7,
+			RecvWindow: 1048560,
+			RecvScale:  4,
+			QScale:     4,
+			Timer:      TCPTimer{Start: 6, Count: 6},
+			ReRecv:     0,
+			KeepAlive:  TCPTimer{Start: 200, Count: 46},
+		},
+	}
+	if !cmp.Equal(want, conns) {
+		t.Errorf("ReadTCPConns: %v", cmp.Diff(want, conns))
+	}
+}
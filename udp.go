@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+)
+
+// UDPConn represents a UDP conversation in /net/udp/N.
+type UDPConn struct {
+	ID         int
+	LocalAddr  net.IP
+	LocalPort  int
+	RemoteAddr net.IP
+	RemotePort int
+	State      string // Open or Closed
+
+	Qin  int // bytes in the input queue
+	Qout int // bytes in the output queue
+}
+
+// ReadUDPConns reads UDP conversations from the UDP directory such as /net/udp.
+func ReadUDPConns(ctx context.Context, opts ...Option) ([]*UDPConn, error) {
+	cfg := newConfig(opts...)
+	ids, err := readDirIDs(cfg.rootdir)
+	if err != nil {
+		return nil, err
+	}
+	var a []*UDPConn
+	for _, id := range ids {
+		c, err := readUDPConn(cfg.rootdir, id)
+		if os.IsNotExist(err) {
+			continue // the conversation is removed while reading
+		}
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, c)
+	}
+	return a, nil
+}
+
+func readUDPConn(protodir string, id int) (*UDPConn, error) {
+	c := UDPConn{ID: id}
+	v, err := readConv(protodir, id, func(s string) error {
+		return parseUDPStatus(s, &c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.LocalAddr, c.LocalPort = v.LocalAddr, v.LocalPort
+	c.RemoteAddr, c.RemotePort = v.RemoteAddr, v.RemotePort
+	return &c, nil
+}
+
+func parseUDPStatus(s string, c *UDPConn) error {
+	fields := strings.Fields(s)
+	if len(fields)%2 != 1 {
+		return errors.New("invalid format")
+	}
+	c.State = fields[0]
+	var p intParser
+	for i := 1; i < len(fields); i += 2 {
+		v := fields[i+1]
+		switch fields[i] {
+		case "qin":
+			c.Qin = p.ParseInt(v, 10)
+		case "qout":
+			c.Qout = p.ParseInt(v, 10)
+		}
+	}
+	return p.Err()
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadUDPConns(t *testing.T) {
+	ctx := context.Background()
+	conns, err := ReadUDPConns(ctx, WithRootDir("testdata/net/udp"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*UDPConn{
+		&UDPConn{
+			ID:         0,
+			LocalAddr:  net.IPv6zero,
+			LocalPort:  0,
+			RemoteAddr: net.IPv6zero,
+			RemotePort: 0,
+			State:      "Closed",
+		},
+		&UDPConn{
+			ID:         1,
+			LocalAddr:  net.IPv4(192, 168, 1, 23).To4(),
+			LocalPort:  5353,
+			RemoteAddr: net.IPv4(192, 168, 1, 5).To4(),
+			RemotePort: 40123,
+			State:      "Open",
+			Qin:        128,
+			Qout:       64,
+		},
+	}
+	if !cmp.Equal(want, conns) {
+		t.Errorf("ReadUDPConns: %v", cmp.Diff(want, conns))
+	}
+}